@@ -1,35 +1,194 @@
 package walker
 
 import (
+	"context"
+	"math"
 	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
 )
 
 type RequestBuilder func(start, fetchCount int) (*http.Request, error)
+type RequestBuilderCtx func(ctx context.Context, start, fetchCount int) (*http.Request, error)
 
 type httpDataSource struct {
 	client         *http.Client
-	requestBuilder RequestBuilder
+	requestBuilder RequestBuilderCtx
+	hosts          *hostLimits
+	config         *config
+	cache          *httpCache
+	authenticator  Authenticator
 }
 
-func (h *httpDataSource) Fetch(start, fetchCount int) (*http.Response, error) {
-	req, err := h.requestBuilder(start, fetchCount)
+func (h *httpDataSource) Fetch(ctx context.Context, start, fetchCount int) (*http.Response, error) {
+	res, err := h.fetch(ctx, start, fetchCount)
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+
+	refresher, ok := h.authenticator.(Refresher)
+	if !ok || !h.config.budget.takeCall() {
+		return res, nil
+	}
+	res.Body.Close()
+
+	if err := refresher.Refresh(ctx, res.Request); err != nil {
+		return nil, err
+	}
+
+	if scope, ok := ctx.Value(scopeKey{}).(*walkScope); ok {
+		if err := scope.rateLimiter.Wait(ctx); err != nil {
+			h.config.budget.refundCall()
+			return nil, err
+		}
+	}
+
+	atomic.AddInt64(&h.config.stats.sourceCalls, 1)
+	return h.fetch(ctx, start, fetchCount)
+}
+
+func (h *httpDataSource) fetch(ctx context.Context, start, fetchCount int) (*http.Response, error) {
+	req, err := h.requestBuilder(ctx, start, fetchCount)
 	if err != nil {
 		return nil, err
 	}
 
+	if h.authenticator != nil {
+		if err := h.authenticator.Authenticate(req); err != nil {
+			return nil, err
+		}
+	}
+
+	release := func() {}
+	if h.hosts != nil {
+		if release, err = h.hosts.acquire(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	var cached *CachedResponse
+	if h.cache != nil {
+		if cached, err = h.cache.prepare(req); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
 	res, err := h.client.Do(req)
 	if err != nil {
+		release()
 		return nil, err
 	}
 
+	res.Body = &releasingBody{ReadCloser: res.Body, release: release}
+	res.Body = &countingBody{ReadCloser: res.Body, budget: h.config.budget}
+
+	if h.cache != nil {
+		var hit bool
+		if res, hit, err = h.cache.resolve(req, res, cached); err != nil {
+			return nil, err
+		}
+		if hit {
+			atomic.AddInt64(&h.config.stats.cacheHits, 1)
+		}
+	}
+
 	return res, nil
 }
 
 func NewApiWalker(client *http.Client, requestBuilder RequestBuilder, sink Sink[*http.Response], options ...Option) *Walker[*http.Response] {
+	return NewApiWalkerCtx(client, AdaptRequestBuilder(requestBuilder), AdaptSink(sink), options...)
+}
+
+func NewApiWalkerCtx(client *http.Client, requestBuilder RequestBuilderCtx, sink SinkCtx[*http.Response], options ...Option) *Walker[*http.Response] {
+	return newApiWalker(client, requestBuilder, sink, newConfig(options...))
+}
+
+func NewApiWalkerE(client *http.Client, requestBuilder RequestBuilder, sink Sink[*http.Response], options ...Option) (*Walker[*http.Response], error) {
+	var requestBuilderCtx RequestBuilderCtx
+	if requestBuilder != nil {
+		requestBuilderCtx = AdaptRequestBuilder(requestBuilder)
+	}
+	var sinkCtx SinkCtx[*http.Response]
+	if sink != nil {
+		sinkCtx = AdaptSink(sink)
+	}
+	return NewApiWalkerCtxE(client, requestBuilderCtx, sinkCtx, options...)
+}
+
+func NewApiWalkerCtxE(client *http.Client, requestBuilder RequestBuilderCtx, sink SinkCtx[*http.Response], options ...Option) (*Walker[*http.Response], error) {
+	errs := ValidationError{}
+	if requestBuilder == nil {
+		errs = append(errs, ErrNilSource)
+	}
+	if sink == nil {
+		errs = append(errs, ErrNilSink)
+	}
+
+	config, err := newValidConfig(errs, options...)
+	if err != nil {
+		return nil, err
+	}
+	return newApiWalker(client, requestBuilder, sink, config), nil
+}
+
+func newApiWalker(client *http.Client, requestBuilder RequestBuilderCtx, sink SinkCtx[*http.Response], config *config) *Walker[*http.Response] {
 	source := &httpDataSource{
 		requestBuilder: requestBuilder,
 		client:         client,
 	}
 
-	return New(source.Fetch, sink, options...)
+	walker := newWalker(source.Fetch, sink, config)
+	source.hosts = newHostLimits(walker.config)
+	source.config = walker.config
+	source.authenticator = walker.authenticator
+	walker.discard = closeResponse
+	walker.retryResult = retryableResponse
+	walker.retryAfter = responseRetryAfter
+	if walker.cache != nil {
+		source.cache = &httpCache{cache: walker.cache}
+	}
+
+	return walker
+}
+
+func closeResponse(res *http.Response) {
+	res.Body.Close()
+}
+
+func retryableResponse(res *http.Response) bool {
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError
+}
+
+func responseRetryAfter(res *http.Response) time.Duration {
+	value := res.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		switch {
+		case seconds < 0:
+			return 0
+		case seconds > int64(math.MaxInt64/time.Second):
+			return math.MaxInt64
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+func AdaptRequestBuilder(requestBuilder RequestBuilder) RequestBuilderCtx {
+	return func(ctx context.Context, start, fetchCount int) (*http.Request, error) {
+		req, err := requestBuilder(start, fetchCount)
+		if err != nil {
+			return nil, err
+		}
+		return req.WithContext(ctx), nil
+	}
 }
@@ -1,12 +1,18 @@
 package walker_test
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/cyucelen/walker"
+	"github.com/cyucelen/walker/walkertest"
 	"github.com/streetbyters/aduket"
 	"github.com/stretchr/testify/assert"
 )
@@ -45,3 +51,140 @@ func TestApiWalker(t *testing.T) {
 	requestRecorder.AssertQueryParamEqual(t, "count", []string{"10"})
 	assert.Equal(t, []byte{'w'}, actualResponseBody)
 }
+
+func TestApiWalkerCancelsInFlightRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	requestBuilder := func(ctx context.Context, start, fetchCount int) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, server.URL, http.NoBody)
+	}
+
+	sink := func(ctx context.Context, res *http.Response, stop func()) error {
+		return nil
+	}
+
+	apiWalker := walker.NewApiWalkerCtx(
+		http.DefaultClient,
+		requestBuilder,
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(10)),
+		walker.WithParallelism(1),
+		walker.WithTaskTimeout(20*time.Millisecond),
+	)
+
+	apiWalker.Walk()
+
+	assert.Len(t, apiWalker.FailedTasks(), 1)
+	assert.ErrorIs(t, apiWalker.FailedTasks()[0].Err, context.DeadlineExceeded)
+}
+
+func TestApiWalkerAgainstFakeServer(t *testing.T) {
+	server := walkertest.NewServer(
+		walkertest.WithStyle(walkertest.CursorStyle),
+		walkertest.WithTotal(250),
+		walkertest.WithFailingPage(100, http.StatusInternalServerError),
+	)
+	defer server.Close()
+
+	var mutex sync.Mutex
+	ids := make([]int, 0)
+	sink := func(res *http.Response, stop func()) error {
+		page, err := walkertest.DecodePage(res)
+		if err != nil {
+			return err
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, item := range page.Items {
+			ids = append(ids, item.ID)
+		}
+		return nil
+	}
+
+	apiWalker := walker.NewApiWalker(
+		http.DefaultClient,
+		server.RequestBuilder(),
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(250)),
+		walker.WithMaxBatchSize(50),
+		walker.WithPagination(walker.CursorPagination{}),
+	)
+	apiWalker.Walk()
+
+	assert.Len(t, apiWalker.FailedTasks(), 1)
+	assert.Equal(t, 100, apiWalker.FailedTasks()[0].Start)
+	assert.Len(t, ids, 200)
+	assert.Equal(t, 5, server.Requests())
+}
+
+func TestApiWalkerHonoursRetryAfter(t *testing.T) {
+	var requests int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	requestBuilder := func(start, fetchCount int) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, server.URL, http.NoBody)
+	}
+
+	apiWalker := walker.NewApiWalker(
+		http.DefaultClient,
+		requestBuilder,
+		func(res *http.Response, stop func()) error { return res.Body.Close() },
+		walker.WithLimiter(walker.ConstantLimiter(10)),
+		walker.WithRetries(1, time.Millisecond),
+	)
+
+	began := time.Now()
+	apiWalker.Walk()
+
+	assert.Empty(t, apiWalker.FailedTasks())
+	assert.Equal(t, int64(2), atomic.LoadInt64(&requests))
+	assert.GreaterOrEqual(t, time.Since(began), time.Second)
+}
+
+func TestApiWalkerRetriesThrottledAndServerErrorResponses(t *testing.T) {
+	statuses := []int{http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusOK}
+	var mutex sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		w.WriteHeader(statuses[requests%len(statuses)])
+		requests++
+	}))
+	defer server.Close()
+
+	requestBuilder := func(start, fetchCount int) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, server.URL, http.NoBody)
+	}
+
+	received := []int{}
+	sink := func(res *http.Response, stop func()) error {
+		received = append(received, res.StatusCode)
+		return res.Body.Close()
+	}
+
+	apiWalker := walker.NewApiWalker(
+		http.DefaultClient,
+		requestBuilder,
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(10)),
+		walker.WithParallelism(1),
+		walker.WithRetries(3, time.Millisecond),
+	)
+	apiWalker.Walk()
+
+	assert.Empty(t, apiWalker.FailedTasks())
+	assert.Equal(t, []int{http.StatusOK}, received)
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, int64(3), apiWalker.Summary().SourceCalls)
+}
@@ -0,0 +1,162 @@
+package walker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const tokenExpiryDelta = 10 * time.Second
+
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+type Refresher interface {
+	Refresh(ctx context.Context, rejected *http.Request) error
+}
+
+type AuthenticatorFunc func(req *http.Request) error
+
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+func BearerToken(token string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+func BasicAuth(username, password string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		req.SetBasicAuth(username, password)
+		return nil
+	})
+}
+
+func APIKeyHeader(name, key string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		req.Header.Set(name, key)
+		return nil
+	})
+}
+
+func APIKeyQuery(name, key string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		query := req.URL.Query()
+		query.Set(name, key)
+		req.URL.RawQuery = query.Encode()
+		return nil
+	})
+}
+
+type Token struct {
+	AccessToken string
+	ExpiresAt   time.Time
+}
+
+type TokenSource func(ctx context.Context) (Token, error)
+
+type refreshingBearer struct {
+	source TokenSource
+	mutex  sync.Mutex
+	token  Token
+}
+
+func RefreshingBearerToken(source TokenSource) Authenticator {
+	return &refreshingBearer{source: source}
+}
+
+func (r *refreshingBearer) Authenticate(req *http.Request) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.token.AccessToken == "" || (!r.token.ExpiresAt.IsZero() && time.Now().Add(tokenExpiryDelta).After(r.token.ExpiresAt)) {
+		if err := r.refresh(req.Context()); err != nil {
+			return err
+		}
+	}
+
+	req.Header.Set("Authorization", "Bearer "+r.token.AccessToken)
+	return nil
+}
+
+func (r *refreshingBearer) Refresh(ctx context.Context, rejected *http.Request) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if rejected != nil && rejected.Header.Get("Authorization") != "Bearer "+r.token.AccessToken {
+		return nil
+	}
+	return r.refresh(ctx)
+}
+
+func (r *refreshingBearer) refresh(ctx context.Context) error {
+	token, err := r.source(ctx)
+	if err != nil {
+		return err
+	}
+	r.token = token
+	return nil
+}
+
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	Client       *http.Client
+}
+
+func OAuth2ClientCredentials(credentials ClientCredentials) Authenticator {
+	if credentials.Client == nil {
+		credentials.Client = http.DefaultClient
+	}
+	return RefreshingBearerToken(credentials.token)
+}
+
+func (c ClientCredentials) token(ctx context.Context) (Token, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+
+	res, err := c.Client.Do(req)
+	if err != nil {
+		return Token{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Token{}, fmt.Errorf("walker: token endpoint responded with status %d", res.StatusCode)
+	}
+
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		return Token{}, err
+	}
+
+	token := Token{AccessToken: payload.AccessToken}
+	if payload.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
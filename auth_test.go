@@ -0,0 +1,284 @@
+package walker_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticAuthenticators(t *testing.T) {
+	tests := []struct {
+		authenticator walker.Authenticator
+		assertRequest func(t *testing.T, r *http.Request)
+	}{
+		{
+			authenticator: walker.BearerToken("secret"),
+			assertRequest: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+			},
+		},
+		{
+			authenticator: walker.BasicAuth("user", "pass"),
+			assertRequest: func(t *testing.T, r *http.Request) {
+				username, password, ok := r.BasicAuth()
+				assert.True(t, ok)
+				assert.Equal(t, "user", username)
+				assert.Equal(t, "pass", password)
+			},
+		},
+		{
+			authenticator: walker.APIKeyHeader("X-Api-Key", "secret"),
+			assertRequest: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+			},
+		},
+		{
+			authenticator: walker.APIKeyQuery("api_key", "secret"),
+			assertRequest: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "secret", r.URL.Query().Get("api_key"))
+				assert.Equal(t, "0", r.URL.Query().Get("page"))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			test.assertRequest(t, r)
+		}))
+
+		requestBuilder := func(start, fetchCount int) (*http.Request, error) {
+			return http.NewRequest(http.MethodGet, fmt.Sprintf("%s?page=%d", server.URL, start), http.NoBody)
+		}
+
+		apiWalker := walker.NewApiWalker(
+			http.DefaultClient,
+			requestBuilder,
+			func(res *http.Response, stop func()) error { return res.Body.Close() },
+			walker.WithLimiter(walker.ConstantLimiter(10)),
+			walker.WithAuthenticator(test.authenticator),
+		)
+		apiWalker.Walk()
+		server.Close()
+
+		assert.Equal(t, int32(1), requests)
+		assert.Empty(t, apiWalker.FailedTasks())
+	}
+}
+
+type tokenEndpoint struct {
+	issued    int32
+	expiresIn int
+}
+
+func (e *tokenEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, _ := r.BasicAuth()
+	if r.FormValue("grant_type") != "client_credentials" || clientID != "walker" || clientSecret != "secret" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	issued := atomic.AddInt32(&e.issued, 1)
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": fmt.Sprintf("token-%d", issued),
+		"token_type":   "bearer",
+		"expires_in":   e.expiresIn,
+	})
+}
+
+func TestOAuth2ClientCredentialsRefreshesOnUnauthorized(t *testing.T) {
+	endpoint := &tokenEndpoint{expiresIn: 3600}
+	tokenServer := httptest.NewServer(endpoint)
+	defer tokenServer.Close()
+
+	var mutex sync.Mutex
+	tokens := make([]string, 0)
+	var requests int32
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if atomic.AddInt32(&requests, 1) > 2 && authorization == "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		tokens = append(tokens, authorization)
+	}))
+	defer apiServer.Close()
+
+	requestBuilder := func(start, fetchCount int) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, apiServer.URL, http.NoBody)
+	}
+
+	var unauthorized int32
+	sink := func(res *http.Response, stop func()) error {
+		if res.StatusCode == http.StatusUnauthorized {
+			atomic.AddInt32(&unauthorized, 1)
+		}
+		return res.Body.Close()
+	}
+
+	apiWalker := walker.NewApiWalker(
+		http.DefaultClient,
+		requestBuilder,
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(40)),
+		walker.WithParallelism(1),
+		walker.WithAuthenticator(walker.OAuth2ClientCredentials(walker.ClientCredentials{
+			TokenURL:     tokenServer.URL,
+			ClientID:     "walker",
+			ClientSecret: "secret",
+			Scopes:       []string{"read"},
+		})),
+	)
+	apiWalker.Walk()
+
+	assert.Empty(t, apiWalker.FailedTasks())
+	assert.Equal(t, int32(0), unauthorized)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&endpoint.issued))
+	assert.Equal(t, []string{"Bearer token-1", "Bearer token-1", "Bearer token-2", "Bearer token-2"}, tokens)
+	assert.Equal(t, int64(5), apiWalker.Summary().SourceCalls)
+}
+
+func TestOAuth2ClientCredentialsRefreshesOnceForParallelUnauthorized(t *testing.T) {
+	endpoint := &tokenEndpoint{expiresIn: 3600}
+	tokenServer := httptest.NewServer(endpoint)
+	defer tokenServer.Close()
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer apiServer.Close()
+
+	requestBuilder := func(start, fetchCount int) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, apiServer.URL, http.NoBody)
+	}
+
+	var unauthorized int32
+	sink := func(res *http.Response, stop func()) error {
+		if res.StatusCode == http.StatusUnauthorized {
+			atomic.AddInt32(&unauthorized, 1)
+		}
+		return res.Body.Close()
+	}
+
+	apiWalker := walker.NewApiWalker(
+		http.DefaultClient,
+		requestBuilder,
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(80)),
+		walker.WithParallelism(8),
+		walker.WithAuthenticator(walker.OAuth2ClientCredentials(walker.ClientCredentials{
+			TokenURL:     tokenServer.URL,
+			ClientID:     "walker",
+			ClientSecret: "secret",
+		})),
+	)
+	apiWalker.Walk()
+
+	assert.Empty(t, apiWalker.FailedTasks())
+	assert.Equal(t, int32(0), unauthorized)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&endpoint.issued))
+}
+
+func TestApiWalkerUnauthorizedRetryWaitsForRateLimiter(t *testing.T) {
+	endpoint := &tokenEndpoint{expiresIn: 3600}
+	tokenServer := httptest.NewServer(endpoint)
+	defer tokenServer.Close()
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer apiServer.Close()
+
+	requestBuilder := func(start, fetchCount int) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, apiServer.URL, http.NoBody)
+	}
+
+	limiter := &countingRateLimiter{}
+	apiWalker := walker.NewApiWalker(
+		http.DefaultClient,
+		requestBuilder,
+		func(res *http.Response, stop func()) error { return res.Body.Close() },
+		walker.WithLimiter(walker.ConstantLimiter(10)),
+		walker.WithParallelism(1),
+		walker.WithRateLimiter(limiter),
+		walker.WithAuthenticator(walker.OAuth2ClientCredentials(walker.ClientCredentials{
+			TokenURL:     tokenServer.URL,
+			ClientID:     "walker",
+			ClientSecret: "secret",
+		})),
+	)
+	apiWalker.Walk()
+
+	assert.Empty(t, apiWalker.FailedTasks())
+	assert.Equal(t, int64(2), apiWalker.Summary().SourceCalls)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&limiter.waits))
+}
+
+func TestOAuth2ClientCredentialsRefreshesExpiredToken(t *testing.T) {
+	endpoint := &tokenEndpoint{expiresIn: 1}
+	tokenServer := httptest.NewServer(endpoint)
+	defer tokenServer.Close()
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer apiServer.Close()
+
+	requestBuilder := func(start, fetchCount int) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, apiServer.URL, http.NoBody)
+	}
+
+	apiWalker := walker.NewApiWalker(
+		http.DefaultClient,
+		requestBuilder,
+		func(res *http.Response, stop func()) error { return res.Body.Close() },
+		walker.WithLimiter(walker.ConstantLimiter(30)),
+		walker.WithParallelism(1),
+		walker.WithAuthenticator(walker.OAuth2ClientCredentials(walker.ClientCredentials{
+			TokenURL:     tokenServer.URL,
+			ClientID:     "walker",
+			ClientSecret: "secret",
+		})),
+	)
+	apiWalker.Walk()
+
+	assert.Empty(t, apiWalker.FailedTasks())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&endpoint.issued))
+}
+
+func TestOAuth2ClientCredentialsTokenEndpointFailure(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer tokenServer.Close()
+
+	requestBuilder := func(start, fetchCount int) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, "http://127.0.0.1:1", http.NoBody)
+	}
+
+	apiWalker := walker.NewApiWalker(
+		http.DefaultClient,
+		requestBuilder,
+		func(res *http.Response, stop func()) error { return nil },
+		walker.WithLimiter(walker.ConstantLimiter(10)),
+		walker.WithAuthenticator(walker.OAuth2ClientCredentials(walker.ClientCredentials{TokenURL: tokenServer.URL})),
+	)
+	apiWalker.Walk()
+
+	assert.Len(t, apiWalker.FailedTasks(), 1)
+	assert.ErrorContains(t, apiWalker.FailedTasks()[0].Err, "status 403")
+}
@@ -0,0 +1,207 @@
+package walker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+type FlushError struct {
+	Tasks []Task
+	Err   error
+}
+
+func (f *FlushError) Error() string {
+	return fmt.Sprintf("walker: flush of items from %d tasks failed: %v", len(f.Tasks), f.Err)
+}
+
+func (f *FlushError) Unwrap() error {
+	return f.Err
+}
+
+type BatchingConfig[I any] struct {
+	MaxItems   int
+	MaxBytes   int
+	Size       func(item I) int
+	MaxLatency time.Duration
+}
+
+type batchOrigin struct {
+	task   Task
+	report failureReporter
+}
+
+type BatchingSink[I any] struct {
+	write      func(ctx context.Context, items []I) error
+	config     BatchingConfig[I]
+	mutex      sync.Mutex
+	writeMutex sync.Mutex
+	items      []I
+	origins    []batchOrigin
+	bytes      int
+	timer      *time.Timer
+}
+
+func NewBatchingSink[I any](write func(ctx context.Context, items []I) error, config BatchingConfig[I]) *BatchingSink[I] {
+	return &BatchingSink[I]{write: write, config: config}
+}
+
+func (b *BatchingSink[I]) Sink(ctx context.Context, items []I, stop func()) error {
+	b.add(ctx, items...)
+	return nil
+}
+
+func (b *BatchingSink[I]) Item(ctx context.Context, item I, stop func()) error {
+	b.add(ctx, item)
+	return nil
+}
+
+func (b *BatchingSink[I]) Flush(ctx context.Context) error {
+	items, origins := b.take(true)
+	return b.flush(ctx, items, origins)
+}
+
+func (b *BatchingSink[I]) add(ctx context.Context, items ...I) {
+	if len(items) == 0 {
+		return
+	}
+
+	origin := batchOrigin{}
+	origin.task, _ = TaskFromContext(ctx)
+	origin.report, _ = ctx.Value(reporterKey{}).(failureReporter)
+
+	b.mutex.Lock()
+	for _, item := range items {
+		b.items = append(b.items, item)
+		b.origins = append(b.origins, origin)
+		if b.config.Size != nil {
+			b.bytes += b.config.Size(item)
+		}
+	}
+
+	if b.config.MaxLatency > 0 && b.timer == nil {
+		walkCtx := context.Background()
+		if scope, ok := ctx.Value(scopeKey{}).(*walkScope); ok {
+			walkCtx = scope.context
+		}
+		b.timer = time.AfterFunc(b.config.MaxLatency, func() {
+			b.flushLate(walkCtx)
+		})
+	}
+
+	bytesExceeded := b.config.MaxBytes > 0 && b.bytes >= b.config.MaxBytes
+	itemsExceeded := b.config.MaxItems > 0 && len(b.items) >= b.config.MaxItems
+	b.mutex.Unlock()
+
+	if bytesExceeded || itemsExceeded {
+		items, origins := b.take(bytesExceeded)
+		b.flush(ctx, items, origins)
+	}
+}
+
+func (b *BatchingSink[I]) flushLate(ctx context.Context) {
+	b.writeMutex.Lock()
+	defer b.writeMutex.Unlock()
+
+	items, origins := b.take(true)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			panicErr, ok := recovered.(*PanicError)
+			if !ok {
+				panicErr = &PanicError{Value: recovered, Stack: debug.Stack()}
+			}
+			reportFlushFailure(origins, panicErr)
+		}
+	}()
+
+	b.writeBatches(ctx, items, origins)
+}
+
+func (b *BatchingSink[I]) take(all bool) ([]I, []batchOrigin) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	count := len(b.items)
+	if !all && b.config.MaxItems > 0 {
+		count -= count % b.config.MaxItems
+	}
+
+	items, origins := b.items[:count], b.origins[:count]
+	b.items = append([]I{}, b.items[count:]...)
+	b.origins = append([]batchOrigin{}, b.origins[count:]...)
+
+	b.bytes = 0
+	if b.config.Size != nil {
+		for _, item := range b.items {
+			b.bytes += b.config.Size(item)
+		}
+	}
+
+	if len(b.items) == 0 && b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	return items, origins
+}
+
+func (b *BatchingSink[I]) flush(ctx context.Context, items []I, origins []batchOrigin) error {
+	b.writeMutex.Lock()
+	defer b.writeMutex.Unlock()
+	return b.writeBatches(ctx, items, origins)
+}
+
+func (b *BatchingSink[I]) writeBatches(ctx context.Context, items []I, origins []batchOrigin) error {
+	var flushErr error
+	for len(items) > 0 {
+		size := len(items)
+		if b.config.MaxItems > 0 && size > b.config.MaxItems {
+			size = b.config.MaxItems
+		}
+
+		if err := b.write(ctx, items[:size]); err != nil {
+			reported := reportFlushFailure(origins[:size], err)
+			if flushErr == nil {
+				flushErr = reported
+			}
+		}
+
+		items, origins = items[size:], origins[size:]
+	}
+
+	return flushErr
+}
+
+func reportFlushFailure(origins []batchOrigin, err error) error {
+	flushErr := &FlushError{Err: err}
+	reported := make(map[Task]struct{})
+	for _, origin := range origins {
+		if _, ok := reported[origin.task]; ok {
+			continue
+		}
+		reported[origin.task] = struct{}{}
+		flushErr.Tasks = append(flushErr.Tasks, origin.task)
+		if origin.report != nil {
+			origin.report(NoItemIndex, err)
+		}
+	}
+	return flushErr
+}
+
+func (w *Walker[T]) flush() {
+	for _, flusher := range w.flushers {
+		err := flusher.Flush(w.context)
+
+		var flushErr *FlushError
+		if err != nil && !errors.As(err, &flushErr) {
+			w.storeFailedItem(0, 0, NoItemIndex, err)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package walker_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+type batchRecorder struct {
+	sync.Mutex
+	batches [][]int
+	failOn  int
+}
+
+func (b *batchRecorder) write(ctx context.Context, items []int) error {
+	b.Lock()
+	defer b.Unlock()
+
+	for _, item := range items {
+		if item == b.failOn {
+			return errors.New("write failed")
+		}
+	}
+	b.batches = append(b.batches, append([]int{}, items...))
+	return nil
+}
+
+func (b *batchRecorder) sizes() []int {
+	b.Lock()
+	defer b.Unlock()
+
+	sizes := make([]int, 0, len(b.batches))
+	for _, batch := range b.batches {
+		sizes = append(sizes, len(batch))
+	}
+	sort.Ints(sizes)
+	return sizes
+}
+
+func TestBatchingSinkFlushesByCountAndAtEndOfWalk(t *testing.T) {
+	recorder := &batchRecorder{}
+	batching := walker.NewBatchingSink(recorder.write, walker.BatchingConfig[int]{MaxItems: 25})
+
+	w := walker.NewCtx(
+		walker.AdaptSource(cursorSource(110)),
+		batching.Sink,
+		walker.WithLimiter(walker.ConstantLimiter(110)),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithFlusher(batching),
+	)
+	w.Walk()
+
+	assert.Empty(t, w.FailedTasks())
+	assert.Equal(t, []int{10, 25, 25, 25, 25}, recorder.sizes())
+}
+
+func TestBatchingSinkFlushesByBytes(t *testing.T) {
+	recorder := &batchRecorder{}
+	batching := walker.NewBatchingSink(recorder.write, walker.BatchingConfig[int]{
+		MaxBytes: 30,
+		Size:     func(item int) int { return 2 },
+	})
+
+	w := walker.NewItemWalkerCtx(
+		walker.AdaptSource(cursorSource(60)),
+		func(page []int) ([]int, error) { return page, nil },
+		batching.Item,
+		walker.WithLimiter(walker.ConstantLimiter(60)),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(1),
+		walker.WithFlusher(batching),
+	)
+	w.Walk()
+
+	assert.Equal(t, []int{15, 15, 15, 15}, recorder.sizes())
+}
+
+func TestBatchingSinkFlushesByLatency(t *testing.T) {
+	recorder := &batchRecorder{}
+	batching := walker.NewBatchingSink(recorder.write, walker.BatchingConfig[int]{
+		MaxItems:   1000,
+		MaxLatency: 10 * time.Millisecond,
+	})
+
+	assert.NoError(t, batching.Sink(context.Background(), []int{1, 2, 3}, func() {}))
+	assert.Empty(t, recorder.sizes())
+
+	assert.Eventually(t, func() bool {
+		return len(recorder.sizes()) == 1
+	}, time.Second, time.Millisecond)
+}
+
+func TestBatchingSinkRecoversPanicsOfLatencyFlushes(t *testing.T) {
+	type walkKey struct{}
+	var mutex sync.Mutex
+	walkValues := make([]any, 0)
+	write := func(ctx context.Context, items []int) error {
+		mutex.Lock()
+		walkValues = append(walkValues, ctx.Value(walkKey{}))
+		mutex.Unlock()
+		if items[0] == 1 {
+			panic("write failed")
+		}
+		return nil
+	}
+	batching := walker.NewBatchingSink(write, walker.BatchingConfig[int]{MaxLatency: 5 * time.Millisecond})
+	source := func(start, fetchCount int) ([]int, error) {
+		time.Sleep(20 * time.Millisecond)
+		return cursorSource(20)(start, fetchCount)
+	}
+
+	w := walker.NewCtx(
+		walker.AdaptSource(source),
+		batching.Sink,
+		walker.WithContext(context.WithValue(context.Background(), walkKey{}, "walk")),
+		walker.WithLimiter(walker.ConstantLimiter(20)),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(1),
+		walker.WithFlusher(batching),
+	)
+	w.Walk()
+
+	failed := w.FailedTasks()
+	if assert.Len(t, failed, 1) {
+		assert.Equal(t, 0, failed[0].Start)
+		var panicErr *walker.PanicError
+		assert.ErrorAs(t, failed[0].Err, &panicErr)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.NotEmpty(t, walkValues)
+	for _, value := range walkValues {
+		assert.Equal(t, "walk", value)
+	}
+}
+
+func TestBatchingSinkMapsFailedFlushToOriginatingTasks(t *testing.T) {
+	recorder := &batchRecorder{failOn: 35}
+	batching := walker.NewBatchingSink(recorder.write, walker.BatchingConfig[int]{MaxItems: 20})
+
+	w := walker.NewCtx(
+		walker.AdaptSource(cursorSource(60)),
+		batching.Sink,
+		walker.WithLimiter(walker.ConstantLimiter(60)),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(1),
+		walker.WithFlusher(batching),
+	)
+	w.Walk()
+
+	failedStarts := make([]int, 0)
+	for _, failedTask := range w.FailedTasks() {
+		assert.Equal(t, walker.NoItemIndex, failedTask.ItemIndex)
+		assert.EqualError(t, failedTask.Err, "write failed")
+		failedStarts = append(failedStarts, failedTask.Start)
+	}
+
+	assert.Len(t, failedStarts, 2)
+	assert.Contains(t, failedStarts, 30)
+	assert.Equal(t, []int{20, 20}, recorder.sizes())
+}
+
+func TestBatchingSinkFlushReturnsFlushError(t *testing.T) {
+	recorder := &batchRecorder{failOn: 2}
+	batching := walker.NewBatchingSink(recorder.write, walker.BatchingConfig[int]{})
+
+	assert.NoError(t, batching.Sink(context.Background(), []int{1, 2, 3}, func() {}))
+
+	var flushErr *walker.FlushError
+	assert.ErrorAs(t, batching.Flush(context.Background()), &flushErr)
+	assert.Len(t, flushErr.Tasks, 1)
+}
@@ -0,0 +1,73 @@
+package walker
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+const Unlimited = -1
+
+type budget struct {
+	calls     int64
+	bytes     int64
+	usedCalls int64
+	usedBytes int64
+}
+
+func newBudget() *budget {
+	return &budget{calls: Unlimited, bytes: Unlimited}
+}
+
+func (b *budget) takeCall() bool {
+	used := atomic.AddInt64(&b.usedCalls, 1)
+	if b.calls != Unlimited && used > b.calls {
+		atomic.AddInt64(&b.usedCalls, -1)
+		return false
+	}
+	return true
+}
+
+func (b *budget) refundCall() {
+	atomic.AddInt64(&b.usedCalls, -1)
+}
+
+func (b *budget) addBytes(n int64) {
+	atomic.AddInt64(&b.usedBytes, n)
+}
+
+func (b *budget) remainingCalls() int64 {
+	return remaining(b.calls, atomic.LoadInt64(&b.usedCalls))
+}
+
+func (b *budget) remainingBytes() int64 {
+	return remaining(b.bytes, atomic.LoadInt64(&b.usedBytes))
+}
+
+func (b *budget) exhausted() bool {
+	return b.remainingCalls() == 0 || b.remainingBytes() == 0
+}
+
+func remaining(limit, used int64) int64 {
+	if limit == Unlimited {
+		return Unlimited
+	}
+	return max64(0, limit-used)
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+type countingBody struct {
+	io.ReadCloser
+	budget *budget
+}
+
+func (c *countingBody) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.budget.addBytes(int64(n))
+	return n, err
+}
@@ -0,0 +1,85 @@
+package walker_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalkerStopsWhenCallBudgetIsExhausted(t *testing.T) {
+	var calls int32
+	mockSink := MockSink{}
+	w := walker.New(
+		countingSource(&calls),
+		mockSink.sink,
+		walker.WithLimiter(walker.InfiniteLimiter()),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(2),
+		walker.WithBudget(5),
+	)
+	w.Walk()
+
+	summary := w.Summary()
+	assert.Equal(t, int32(5), calls)
+	assert.Equal(t, int64(5), summary.SourceCalls)
+	assert.Equal(t, int64(0), summary.RemainingCalls)
+	assert.Equal(t, int64(walker.Unlimited), summary.RemainingBytes)
+	assert.True(t, summary.BudgetExhausted)
+	assert.True(t, summary.Stopped)
+	assert.Equal(t, makeExpectedOutput(50, 10), mockSink.sortedResults())
+}
+
+func TestWalkerSummaryWithoutBudget(t *testing.T) {
+	w := walker.New(
+		cursorSource(100),
+		(&MockSink{}).sink,
+		walker.WithLimiter(walker.ConstantLimiter(100)),
+		walker.WithPagination(walker.CursorPagination{}),
+	)
+	w.Walk()
+
+	summary := w.Summary()
+	assert.Equal(t, int64(10), summary.SourceCalls)
+	assert.Equal(t, int64(walker.Unlimited), summary.RemainingCalls)
+	assert.False(t, summary.BudgetExhausted)
+	assert.False(t, summary.Stopped)
+}
+
+func TestApiWalkerStopsWhenByteBudgetIsExhausted(t *testing.T) {
+	page := bytes.Repeat([]byte{'w'}, 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(page)
+	}))
+	defer server.Close()
+
+	requestBuilder := func(start, fetchCount int) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, server.URL, http.NoBody)
+	}
+
+	sink := func(res *http.Response, stop func()) error {
+		defer res.Body.Close()
+		_, err := io.Copy(io.Discard, res.Body)
+		return err
+	}
+
+	apiWalker := walker.NewApiWalker(
+		http.DefaultClient,
+		requestBuilder,
+		sink,
+		walker.WithParallelism(1),
+		walker.WithByteBudget(250),
+	)
+	apiWalker.Walk()
+
+	summary := apiWalker.Summary()
+	assert.True(t, summary.Stopped)
+	assert.True(t, summary.BudgetExhausted)
+	assert.Equal(t, int64(0), summary.RemainingBytes)
+	assert.GreaterOrEqual(t, summary.BytesRead, int64(250))
+	assert.Less(t, summary.SourceCalls, int64(10))
+}
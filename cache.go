@@ -0,0 +1,172 @@
+package walker
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+type Cache interface {
+	Get(key string) (*CachedResponse, bool, error)
+	Set(key string, response *CachedResponse) error
+}
+
+type memoryCache struct {
+	mutex     sync.RWMutex
+	responses map[string]*CachedResponse
+}
+
+func NewMemoryCache() Cache {
+	return &memoryCache{responses: make(map[string]*CachedResponse)}
+}
+
+func (m *memoryCache) Get(key string) (*CachedResponse, bool, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	response, ok := m.responses[key]
+	return response, ok, nil
+}
+
+func (m *memoryCache) Set(key string, response *CachedResponse) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.responses[key] = response
+	return nil
+}
+
+type diskCache struct {
+	dir string
+}
+
+func NewDiskCache(dir string) (Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &diskCache{dir: dir}, nil
+}
+
+func (d *diskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (d *diskCache) Get(key string) (*CachedResponse, bool, error) {
+	data, err := os.ReadFile(d.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var response CachedResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, false, err
+	}
+
+	return &response, true, nil
+}
+
+func (d *diskCache) Set(key string, response *CachedResponse) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(d.dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), d.path(key))
+}
+
+type httpCache struct {
+	cache Cache
+}
+
+func (h *httpCache) prepare(req *http.Request) (*CachedResponse, error) {
+	if req.Method != http.MethodGet {
+		return nil, nil
+	}
+
+	cached, ok, err := h.cache.Get(req.URL.String())
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	if etag := cached.Header.Get("ETag"); etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	return cached, nil
+}
+
+func (h *httpCache) resolve(req *http.Request, res *http.Response, cached *CachedResponse) (*http.Response, bool, error) {
+	if res.StatusCode == http.StatusNotModified && cached != nil {
+		res.Body.Close()
+		return cached.response(req), true, nil
+	}
+
+	if req.Method != http.MethodGet || res.StatusCode != http.StatusOK {
+		return res, false, nil
+	}
+
+	if res.Header.Get("ETag") == "" && res.Header.Get("Last-Modified") == "" {
+		return res, false, nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = h.cache.Set(req.URL.String(), &CachedResponse{StatusCode: res.StatusCode, Header: res.Header.Clone(), Body: body})
+	if err != nil {
+		return nil, false, err
+	}
+
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	return res, false, nil
+}
+
+func (c *CachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(c.StatusCode),
+		StatusCode:    c.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        c.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       req,
+	}
+}
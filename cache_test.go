@@ -0,0 +1,88 @@
+package walker_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+func newETagServer(notModified *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := fmt.Sprintf(`"page-%s"`, r.URL.Query().Get("page"))
+		if r.Header.Get("If-None-Match") == etag {
+			atomic.AddInt32(notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprintf(w, "page %s", r.URL.Query().Get("page"))
+	}))
+}
+
+func walkWithCache(t *testing.T, server *httptest.Server, cache walker.Cache) ([]string, walker.Summary) {
+	requestBuilder := func(start, fetchCount int) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, fmt.Sprintf("%s?page=%d", server.URL, start), http.NoBody)
+	}
+
+	var mutex sync.Mutex
+	bodies := make([]string, 0)
+	sink := func(res *http.Response, stop func()) error {
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		mutex.Lock()
+		defer mutex.Unlock()
+		bodies = append(bodies, string(body))
+		return err
+	}
+
+	apiWalker := walker.NewApiWalker(
+		http.DefaultClient,
+		requestBuilder,
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(30)),
+		walker.WithCache(cache),
+	)
+	apiWalker.Walk()
+	assert.Empty(t, apiWalker.FailedTasks())
+
+	sort.Strings(bodies)
+	return bodies, apiWalker.Summary()
+}
+
+func TestApiWalkerServesCachedBodyOnNotModified(t *testing.T) {
+	diskCache, err := walker.NewDiskCache(t.TempDir())
+	assert.NoError(t, err)
+
+	caches := map[string]walker.Cache{
+		"memory": walker.NewMemoryCache(),
+		"disk":   diskCache,
+	}
+
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			var notModified int32
+			server := newETagServer(&notModified)
+			defer server.Close()
+
+			expectedBodies := []string{"page 0", "page 1", "page 2"}
+
+			bodies, summary := walkWithCache(t, server, cache)
+			assert.Equal(t, expectedBodies, bodies)
+			assert.Equal(t, int64(0), summary.CacheHits)
+			assert.Equal(t, int32(0), notModified)
+
+			bodies, summary = walkWithCache(t, server, cache)
+			assert.Equal(t, expectedBodies, bodies)
+			assert.Equal(t, int64(3), summary.CacheHits)
+			assert.Equal(t, int32(3), notModified)
+		})
+	}
+}
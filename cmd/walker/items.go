@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+func extractItems(body io.Reader, path string) ([]json.RawMessage, error) {
+	var value json.RawMessage
+	if err := json.NewDecoder(body).Decode(&value); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	if path != "" {
+		for _, segment := range strings.Split(path, ".") {
+			var err error
+			if value, err = lookup(value, segment); err != nil {
+				return nil, fmt.Errorf("items path %q: %w", path, err)
+			}
+		}
+	}
+
+	if isNull(value) {
+		return nil, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(value, &items); err != nil {
+		return nil, fmt.Errorf("items path %q does not point to an array", path)
+	}
+	return items, nil
+}
+
+func lookup(value json.RawMessage, segment string) (json.RawMessage, error) {
+	if isNull(value) {
+		return value, nil
+	}
+
+	if index, err := strconv.Atoi(segment); err == nil {
+		var array []json.RawMessage
+		if err := json.Unmarshal(value, &array); err == nil {
+			if index < 0 || index >= len(array) {
+				return nil, fmt.Errorf("index %d out of range", index)
+			}
+			return array[index], nil
+		}
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(value, &object); err != nil {
+		return nil, fmt.Errorf("%q is not an object", segment)
+	}
+	return object[segment], nil
+}
+
+func isNull(value json.RawMessage) bool {
+	trimmed := strings.TrimSpace(string(value))
+	return trimmed == "" || trimmed == "null"
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"github.com/cyucelen/walker/sinks"
+	"github.com/cyucelen/walker/walkerconfig"
+)
+
+const usage = `Walks a paginated HTTP API and writes its items as JSON Lines.
+
+Usage:
+  walker -url 'https://api.example.com/items?page={{.Start}}&per_page={{.Count}}' [flags]
+
+Flags:
+`
+
+type options struct {
+	url         string
+	method      string
+	headers     headerFlags
+	pagination  string
+	limit       int
+	batchSize   int
+	parallelism int
+	rate        string
+	items       string
+	end         string
+	output      string
+	gzip        bool
+	maxBytes    int64
+	timeout     time.Duration
+	maxFailures int
+}
+
+type headerFlags []string
+
+func (h *headerFlags) String() string {
+	return strings.Join(*h, ", ")
+}
+
+func (h *headerFlags) Set(value string) error {
+	if !strings.Contains(value, ":") {
+		return fmt.Errorf("header %q must be in \"Name: value\" form", value)
+	}
+	*h = append(*h, value)
+	return nil
+}
+
+func parseFlags(args []string, stderr io.Writer) (options, error) {
+	var opts options
+	flags := flag.NewFlagSet("walker", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&opts.url, "url", "", "URL template, {{.Start}} and {{.Count}} are replaced with the page position and size")
+	flags.StringVar(&opts.method, "method", http.MethodGet, "HTTP method of the requests")
+	flags.Var(&opts.headers, "H", "request header in \"Name: value\" form, can be repeated")
+	flags.StringVar(&opts.pagination, "pagination", "offset", "pagination style: offset (page number) or cursor (item index)")
+	flags.IntVar(&opts.limit, "limit", 0, "max number of items to fetch, 0 walks until the end of data")
+	flags.IntVar(&opts.batchSize, "batch-size", 10, "number of items requested per page")
+	flags.IntVar(&opts.parallelism, "parallelism", 4, "number of concurrent requests")
+	flags.StringVar(&opts.rate, "rate", "", "rate limit of requests, e.g. 10/s or 100/1m")
+	flags.StringVar(&opts.items, "items", "", "dot separated JSON path of the items array in the response, e.g. data.items")
+	flags.StringVar(&opts.end, "end", "empty", "end of data rule: empty (page without items), short (page with less items than requested) or none")
+	flags.StringVar(&opts.output, "o", "-", "output file, - writes to stdout")
+	flags.BoolVar(&opts.gzip, "gzip", false, "gzip the output file")
+	flags.Int64Var(&opts.maxBytes, "max-bytes", 0, "rotate the output file after the given size")
+	flags.DurationVar(&opts.timeout, "timeout", 30*time.Second, "timeout of each request")
+	flags.IntVar(&opts.maxFailures, "max-failures", 5, "stop the walk after the given number of failed pages")
+
+	if err := flags.Parse(args); err != nil {
+		return opts, err
+	}
+
+	switch {
+	case opts.url == "":
+		return opts, errors.New("-url is required")
+	case opts.pagination != "offset" && opts.pagination != "cursor":
+		return opts, fmt.Errorf("unknown -pagination %q", opts.pagination)
+	case opts.end != "empty" && opts.end != "short" && opts.end != "none":
+		return opts, fmt.Errorf("unknown -end %q", opts.end)
+	case opts.end == "none" && opts.limit <= 0:
+		return opts, errors.New("-end none requires a -limit")
+	case opts.output == "-" && (opts.gzip || opts.maxBytes != 0):
+		return opts, errors.New("-gzip and -max-bytes require an -o file")
+	case opts.maxFailures <= 0:
+		return opts, errors.New("-max-failures must be positive")
+	}
+	return opts, nil
+}
+
+func parseRate(rate string) (int, time.Duration, error) {
+	count, per, ok := strings.Cut(rate, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("rate %q must be in count/duration form", rate)
+	}
+
+	n, err := strconv.Atoi(count)
+	if err != nil || n <= 0 {
+		return 0, 0, fmt.Errorf("rate %q must have a positive count", rate)
+	}
+
+	if per != "" && !strings.ContainsAny(per[:1], "0123456789") {
+		per = "1" + per
+	}
+	duration, err := time.ParseDuration(per)
+	if err != nil || duration <= 0 {
+		return 0, 0, fmt.Errorf("rate %q must have a positive duration", rate)
+	}
+	return n, duration, nil
+}
+
+type itemWriter interface {
+	Write(items ...json.RawMessage) error
+	Close() error
+}
+
+type streamWriter struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+func (s *streamWriter) Write(items ...json.RawMessage) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, item := range items {
+		if err := s.encoder.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *streamWriter) Close() error {
+	return nil
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	opts, err := parseFlags(args, stderr)
+	if err != nil {
+		return err
+	}
+
+	urlTemplate, err := walkerconfig.NewTemplate("url", opts.url)
+	if err != nil {
+		return fmt.Errorf("invalid -url template: %w", err)
+	}
+
+	var writer itemWriter = &streamWriter{encoder: json.NewEncoder(stdout)}
+	if opts.output != "-" {
+		fileOptions := []sinks.FileOption{sinks.WithMaxBytes(opts.maxBytes)}
+		if opts.gzip {
+			fileOptions = append(fileOptions, sinks.WithGzip())
+		}
+		writer = sinks.NewJSONLines[json.RawMessage](opts.output, fileOptions...)
+	}
+
+	walkerOptions := []walker.Option{
+		walker.WithContext(ctx),
+		walker.WithMaxBatchSize(opts.batchSize),
+		walker.WithParallelism(opts.parallelism),
+		walker.WithTaskTimeout(opts.timeout),
+	}
+	if opts.pagination == "cursor" {
+		walkerOptions = append(walkerOptions, walker.WithPagination(walker.CursorPagination{}))
+	}
+	if opts.limit > 0 {
+		walkerOptions = append(walkerOptions, walker.WithLimiter(walker.ConstantLimiter(opts.limit)))
+	}
+	if opts.rate != "" {
+		count, per, err := parseRate(opts.rate)
+		if err != nil {
+			return err
+		}
+		walkerOptions = append(walkerOptions, walker.WithRateLimit(count, per))
+	}
+
+	buildRequest := func(ctx context.Context, start, fetchCount int) (*http.Request, error) {
+		var url strings.Builder
+		if err := urlTemplate.Execute(&url, struct{ Start, Count int }{start, fetchCount}); err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, opts.method, url.String(), http.NoBody)
+		if err != nil {
+			return nil, err
+		}
+		for _, header := range opts.headers {
+			name, value, _ := strings.Cut(header, ":")
+			req.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+		return req, nil
+	}
+
+	var failures int64
+	fail := func(err error, stop func()) error {
+		if atomic.AddInt64(&failures, 1) >= int64(opts.maxFailures) {
+			stop()
+		}
+		return err
+	}
+
+	sink := func(ctx context.Context, res *http.Response, stop func()) error {
+		if res == nil {
+			return fail(nil, stop)
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode < 200 || res.StatusCode > 299 {
+			return fail(fmt.Errorf("%s %s: unexpected status %s", res.Request.Method, res.Request.URL, res.Status), stop)
+		}
+
+		items, err := extractItems(res.Body, opts.items)
+		if err != nil {
+			return fail(fmt.Errorf("%s: %w", res.Request.URL, err), stop)
+		}
+
+		task, _ := walker.TaskFromContext(ctx)
+		if (opts.end == "empty" && len(items) == 0) || (opts.end == "short" && len(items) < task.FetchCount) {
+			stop()
+		}
+		return writer.Write(items...)
+	}
+
+	w, err := walker.NewApiWalkerCtxE(http.DefaultClient, buildRequest, sink, walkerOptions...)
+	if err != nil {
+		writer.Close()
+		return err
+	}
+	w.Walk()
+
+	closeErr := writer.Close()
+	for _, failed := range w.FailedTasks() {
+		fmt.Fprintf(stderr, "walker: task start=%d count=%d failed: %v\n", failed.Start, failed.FetchCount, failed.Err)
+	}
+	if failed := len(w.FailedTasks()); failed > 0 {
+		return fmt.Errorf("%d tasks failed", failed)
+	}
+	return closeErr
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "walker:", err)
+		os.Exit(1)
+	}
+}
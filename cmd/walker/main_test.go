@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker/walkertest"
+	"github.com/stretchr/testify/assert"
+)
+
+func itemIDs(t *testing.T, output []byte) []int {
+	ids := []int{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		var item walkertest.Item
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &item))
+		ids = append(ids, item.ID)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func sequence(from, to int) []int {
+	ids := []int{}
+	for id := from; id <= to; id++ {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestRunWalksUntilEmptyPage(t *testing.T) {
+	server := walkertest.NewServer(walkertest.WithTotal(95))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{
+		"-url", server.URL + "/items?page={{.Start}}&per_page={{.Count}}",
+		"-items", "items",
+		"-H", "Accept: application/json",
+		"-parallelism", "2",
+		"-rate", "1000/s",
+	}, &stdout, &stderr)
+
+	assert.Nil(t, err)
+	assert.Empty(t, stderr.String())
+	assert.Equal(t, sequence(1, 95), itemIDs(t, stdout.Bytes()))
+}
+
+func TestRunWritesCursorPagesToFile(t *testing.T) {
+	server := walkertest.NewServer(walkertest.WithStyle(walkertest.CursorStyle), walkertest.WithTotal(1000))
+	defer server.Close()
+	output := filepath.Join(t.TempDir(), "items.jsonl")
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{
+		"-url", server.URL + "/items?start={{.Start}}&count={{.Count}}",
+		"-items", "items",
+		"-pagination", "cursor",
+		"-limit", "45",
+		"-end", "none",
+		"-o", output,
+	}, &stdout, &stderr)
+	assert.Nil(t, err)
+	assert.Empty(t, stdout.String())
+
+	content, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	assert.Equal(t, sequence(1, 45), itemIDs(t, content))
+}
+
+func TestRunReportsFailedPages(t *testing.T) {
+	server := walkertest.NewServer(walkertest.WithTotal(30), walkertest.WithFailingPage(1, http.StatusInternalServerError))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{
+		"-url", server.URL + "/items?page={{.Start}}&per_page={{.Count}}",
+		"-items", "items",
+		"-limit", "30",
+		"-end", "none",
+		"-timeout", time.Second.String(),
+	}, &stdout, &stderr)
+
+	assert.EqualError(t, err, "1 tasks failed")
+	assert.Contains(t, stderr.String(), "unexpected status 500")
+	assert.Equal(t, append(sequence(1, 10), sequence(21, 30)...), itemIDs(t, stdout.Bytes()))
+}
+
+func TestRunStopsWhenUpstreamKeepsFailing(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	for _, url := range []string{failing.URL + "/items?page={{.Start}}", "http://127.0.0.1:1/items?page={{.Start}}"} {
+		var stdout, stderr bytes.Buffer
+		done := make(chan error, 1)
+		go func() {
+			done <- run(context.Background(), []string{"-url", url, "-parallelism", "1", "-max-failures", "3"}, &stdout, &stderr)
+		}()
+
+		select {
+		case err := <-done:
+			assert.True(t, err != nil && strings.HasSuffix(err.Error(), "tasks failed"), "%s: %v", url, err)
+			assert.Empty(t, stdout.String())
+		case <-time.After(5 * time.Second):
+			t.Fatalf("walk of %s did not stop", url)
+		}
+	}
+}
+
+func TestParseFlagsRejectsInvalidOptions(t *testing.T) {
+	testCases := map[string][]string{
+		"-url is required":                              {},
+		`unknown -pagination "page"`:                    {"-url", "x", "-pagination", "page"},
+		`unknown -end "never"`:                          {"-url", "x", "-end", "never"},
+		"-end none requires a -limit":                   {"-url", "x", "-end", "none"},
+		`header "Accept" must be in "Name: value" form`: {"-url", "x", "-H", "Accept"},
+		"-gzip and -max-bytes require an -o file":       {"-url", "x", "-gzip"},
+		"-max-bytes require an -o file":                 {"-url", "x", "-o", "-", "-max-bytes", "10"},
+		"-max-failures must be positive":                {"-url", "x", "-max-failures", "0"},
+	}
+
+	for expected, args := range testCases {
+		_, err := parseFlags(args, &bytes.Buffer{})
+		assert.True(t, err != nil && strings.Contains(err.Error(), expected), "%v: %v", args, err)
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	count, per, err := parseRate("10/s")
+	assert.Nil(t, err)
+	assert.Equal(t, 10, count)
+	assert.Equal(t, time.Second, per)
+
+	count, per, err = parseRate("100/5m")
+	assert.Nil(t, err)
+	assert.Equal(t, 100, count)
+	assert.Equal(t, 5*time.Minute, per)
+
+	_, _, err = parseRate("fast")
+	assert.NotNil(t, err)
+}
+
+func TestExtractItems(t *testing.T) {
+	items, err := extractItems(strings.NewReader(`{"data": {"results": [{"id": 1}, {"id": 2}]}}`), "data.results")
+	assert.Nil(t, err)
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`{"id": 1}`), json.RawMessage(`{"id": 2}`)}, items)
+
+	items, err = extractItems(strings.NewReader(`[[{"id": 3}]]`), "0")
+	assert.Nil(t, err)
+	assert.Len(t, items, 1)
+
+	items, err = extractItems(strings.NewReader(`{"data": null}`), "data.results")
+	assert.Nil(t, err)
+	assert.Empty(t, items)
+
+	_, err = extractItems(strings.NewReader(`{"data": 1}`), "data")
+	assert.EqualError(t, err, `items path "data" does not point to an array`)
+}
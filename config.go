@@ -8,13 +8,28 @@ import (
 type Option func(*config)
 
 type config struct {
-	maxBatchSize  int
-	parallelism   int
-	pagination    Pagination
-	limiter       Limiter
-	rateLimit     rateLimit
-	context       context.Context
-	contextCancel context.CancelFunc
+	maxBatchSize       int
+	parallelism        int
+	pagination         Pagination
+	limiter            Limiter
+	rateLimiters       []RateLimiter
+	sharedRateLimiters []*SharedRateLimiter
+	context            context.Context
+	contextCancel      context.CancelFunc
+	panicPolicy        PanicPolicy
+	taskTimeout        time.Duration
+	hedging            *hedging
+	retries            retries
+	budget             *budget
+	stats              *stats
+	flushers           []Flusher
+	invalid            []error
+
+	requestKey      RequestKey
+	hostRateLimiter func(key string) RateLimiter
+	hostConcurrency int
+	cache           Cache
+	authenticator   Authenticator
 }
 
 func WithMaxBatchSize(size int) Option {
@@ -49,6 +64,105 @@ func WithContext(ctx context.Context) Option {
 
 func WithRateLimit(count int, per time.Duration) Option {
 	return func(c *config) {
-		c.rateLimit = rateLimit{count: count, per: per}
+		if count <= 0 || per <= 0 {
+			c.invalid = append(c.invalid, invalidOption("WithRateLimit", "count and per must be positive, got %d per %s", count, per))
+			return
+		}
+		c.rateLimiters = append(c.rateLimiters, NewEvenRateLimiter(count, per))
+	}
+}
+
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(c *config) {
+		c.rateLimiters = append(c.rateLimiters, limiter)
+	}
+}
+
+func WithSharedRateLimiter(shared *SharedRateLimiter) Option {
+	return func(c *config) {
+		c.sharedRateLimiters = append(c.sharedRateLimiters, shared)
+	}
+}
+
+func WithPanicPolicy(policy PanicPolicy) Option {
+	return func(c *config) {
+		c.panicPolicy = policy
+	}
+}
+
+func WithTaskTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.taskTimeout = timeout
+	}
+}
+
+func WithRetries(attempts int, backoff time.Duration) Option {
+	return func(c *config) {
+		c.retries = retries{attempts: attempts, backoff: backoff}
+	}
+}
+
+func WithHedging(percentile float64) Option {
+	return func(c *config) {
+		c.hedging = &hedging{percentile: percentile, minSamples: defaultHedgingMinSamples}
+	}
+}
+
+func WithRequestKey(key RequestKey) Option {
+	return func(c *config) {
+		c.requestKey = key
+	}
+}
+
+func WithPerHostRateLimit(count int, per time.Duration) Option {
+	if count <= 0 || per <= 0 {
+		return func(c *config) {
+			c.invalid = append(c.invalid, invalidOption("WithPerHostRateLimit", "count and per must be positive, got %d per %s", count, per))
+		}
+	}
+	return WithPerHostRateLimiter(func(string) RateLimiter {
+		return NewEvenRateLimiter(count, per)
+	})
+}
+
+func WithPerHostRateLimiter(newLimiter func(key string) RateLimiter) Option {
+	return func(c *config) {
+		c.hostRateLimiter = newLimiter
+	}
+}
+
+func WithPerHostConcurrency(max int) Option {
+	return func(c *config) {
+		c.hostConcurrency = max
+	}
+}
+
+func WithBudget(calls int64) Option {
+	return func(c *config) {
+		c.budget.calls = calls
+	}
+}
+
+func WithByteBudget(bytes int64) Option {
+	return func(c *config) {
+		c.budget.bytes = bytes
+	}
+}
+
+func WithCache(cache Cache) Option {
+	return func(c *config) {
+		c.cache = cache
+	}
+}
+
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(c *config) {
+		c.authenticator = authenticator
+	}
+}
+
+func WithFlusher(flusher Flusher) Option {
+	return func(c *config) {
+		c.flushers = append(c.flushers, flusher)
 	}
 }
@@ -0,0 +1,132 @@
+package walker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+type taskTracker struct {
+	mutex   sync.Mutex
+	count   int
+	waiters []chan struct{}
+}
+
+func (t *taskTracker) add() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.count++
+}
+
+func (t *taskTracker) done() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.count--
+	if t.count > 0 {
+		return
+	}
+
+	for _, waiter := range t.waiters {
+		close(waiter)
+	}
+	t.waiters = nil
+}
+
+func (t *taskTracker) idle() <-chan struct{} {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	waiter := make(chan struct{})
+	if t.count == 0 {
+		close(waiter)
+		return waiter
+	}
+
+	t.waiters = append(t.waiters, waiter)
+	return waiter
+}
+
+type pauser struct {
+	mutex   sync.Mutex
+	resumed chan struct{}
+}
+
+func (p *pauser) pause() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.resumed == nil {
+		p.resumed = make(chan struct{})
+	}
+}
+
+func (p *pauser) resume() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.resumed != nil {
+		close(p.resumed)
+		p.resumed = nil
+	}
+}
+
+func (p *pauser) wait() <-chan struct{} {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.resumed == nil {
+		return closedChannel
+	}
+	return p.resumed
+}
+
+func (p *pauser) isPaused() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.resumed != nil
+}
+
+var closedChannel = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+func (w *Walker[T]) Pause() {
+	w.pauser.pause()
+}
+
+func (w *Walker[T]) Resume() {
+	w.pauser.resume()
+}
+
+func (w *Walker[T]) IsPaused() bool {
+	return w.pauser.isPaused()
+}
+
+func (w *Walker[T]) Drain(ctx context.Context) error {
+	w.Stop()
+
+	drained := make(chan struct{})
+	go func() {
+		<-w.tasks.idle()
+		w.children.Wait()
+		if atomic.LoadInt32(&w.walking) == 1 {
+			<-w.finished
+		}
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		w.contextCancel()
+		return ctx.Err()
+	}
+}
+
+func (w *Walker[T]) waitWhilePaused() {
+	select {
+	case <-w.pauser.wait():
+	case <-w.stopped:
+	case <-w.context.Done():
+	}
+}
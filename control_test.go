@@ -0,0 +1,186 @@
+package walker_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+func countingSource(calls *int32) walker.Source[[]int] {
+	return func(start, fetchCount int) ([]int, error) {
+		atomic.AddInt32(calls, 1)
+		return cursorSource(100)(start, fetchCount)
+	}
+}
+
+func TestWalkerPauseAndResume(t *testing.T) {
+	var calls int32
+	mockSink := MockSink{}
+	w := walker.New(
+		countingSource(&calls),
+		mockSink.sink,
+		walker.WithLimiter(walker.ConstantLimiter(100)),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(1),
+	)
+
+	w.Pause()
+	assert.True(t, w.IsPaused())
+
+	done := make(chan struct{})
+	go func() {
+		w.Walk()
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	w.Resume()
+	assert.False(t, w.IsPaused())
+	<-done
+
+	assert.Equal(t, int32(10), atomic.LoadInt32(&calls))
+	assert.Equal(t, makeExpectedOutput(100, 10), mockSink.sortedResults())
+}
+
+func TestWalkerDrainWaitsForInFlightSinks(t *testing.T) {
+	var sinked int32
+	release := make(chan struct{})
+	sink := func(result []int, stop func()) error {
+		<-release
+		atomic.AddInt32(&sinked, 1)
+		return nil
+	}
+
+	w := walker.New(
+		cursorSource(100),
+		sink,
+		walker.WithLimiter(walker.InfiniteLimiter()),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(2),
+	)
+	go w.Walk()
+
+	time.Sleep(20 * time.Millisecond)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+
+	err := w.Drain(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, w.IsStopped())
+	assert.NotZero(t, atomic.LoadInt32(&sinked))
+}
+
+func TestWalkerDrainWaitsForSpawnedWalks(t *testing.T) {
+	var detailed int32
+	listSink := func(ctx context.Context, ids []int, stop func()) error {
+		child := walker.NewCtx(
+			func(ctx context.Context, start, fetchCount int) ([]int, error) {
+				time.Sleep(30 * time.Millisecond)
+				return []int{start}, nil
+			},
+			func(ctx context.Context, ids []int, stop func()) error {
+				atomic.AddInt32(&detailed, 1)
+				return nil
+			},
+			walker.WithLimiter(walker.ConstantLimiter(1)),
+		)
+		return walker.Spawn(ctx, child)
+	}
+
+	w := walker.NewCtx(
+		walker.AdaptSource(cursorSource(100)),
+		listSink,
+		walker.WithLimiter(walker.ConstantLimiter(10)),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(1),
+	)
+	go w.Walk()
+
+	time.Sleep(10 * time.Millisecond)
+	err := w.Drain(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&detailed))
+}
+
+func TestWalkerDrainWaitsForFinalFlush(t *testing.T) {
+	recorder := &batchRecorder{}
+	batching := walker.NewBatchingSink(recorder.write, walker.BatchingConfig[int]{MaxItems: 1000})
+	source := func(start, fetchCount int) ([]int, error) {
+		time.Sleep(10 * time.Millisecond)
+		return cursorSource(100)(start, fetchCount)
+	}
+
+	w := walker.NewCtx(
+		walker.AdaptSource(source),
+		batching.Sink,
+		walker.WithLimiter(walker.ConstantLimiter(100)),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(1),
+		walker.WithFlusher(batching),
+	)
+	go w.Walk()
+
+	time.Sleep(25 * time.Millisecond)
+	err := w.Drain(context.Background())
+
+	assert.NoError(t, err)
+	sizes := recorder.sizes()
+	if assert.Len(t, sizes, 1) {
+		assert.Less(t, sizes[0], 100)
+	}
+}
+
+func TestWalkerDrainDeadlineExceeded(t *testing.T) {
+	sink := func(result []int, stop func()) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	w := walker.New(
+		cursorSource(100),
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(100)),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(1),
+	)
+	go w.Walk()
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, w.Drain(ctx), context.DeadlineExceeded)
+}
+
+func TestWalkerStopWakesPausedWalk(t *testing.T) {
+	w := walker.New(
+		cursorSource(100),
+		(&MockSink{}).sink,
+		walker.WithLimiter(walker.ConstantLimiter(100)),
+	)
+	w.Pause()
+
+	done := make(chan struct{})
+	go func() {
+		w.Walk()
+		close(done)
+	}()
+
+	w.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("walk did not return after stop")
+	}
+}
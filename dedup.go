@@ -0,0 +1,117 @@
+package walker
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+type DedupStore interface {
+	Add(key string) bool
+}
+
+type memoryDedupStore struct {
+	mutex sync.Mutex
+	keys  map[string]struct{}
+}
+
+func NewMemoryDedupStore() DedupStore {
+	return &memoryDedupStore{keys: make(map[string]struct{})}
+}
+
+func (m *memoryDedupStore) Add(key string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.keys[key]; ok {
+		return false
+	}
+	m.keys[key] = struct{}{}
+	return true
+}
+
+type bloomDedupStore struct {
+	mutex  sync.Mutex
+	bits   []uint64
+	size   uint64
+	hashes uint64
+}
+
+func NewBloomDedupStore(expectedItems int, falsePositiveRate float64) (DedupStore, error) {
+	if expectedItems <= 0 {
+		return nil, fmt.Errorf("walker: bloom dedup store expected items must be positive, got %d", expectedItems)
+	}
+	if !(falsePositiveRate > 0 && falsePositiveRate < 1) {
+		return nil, fmt.Errorf("walker: bloom dedup store false positive rate must be in (0, 1), got %g", falsePositiveRate)
+	}
+
+	size := math.Ceil(-float64(expectedItems) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	hashes := math.Max(1, math.Round(size/float64(expectedItems)*math.Ln2))
+
+	return &bloomDedupStore{
+		bits:   make([]uint64, int(size)/64+1),
+		size:   uint64(size),
+		hashes: uint64(hashes),
+	}, nil
+}
+
+func (b *bloomDedupStore) Add(key string) bool {
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+	sum := hash.Sum64()
+	h1, h2 := sum&math.MaxUint32, sum>>32|1
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	added := false
+	for i := uint64(0); i < b.hashes; i++ {
+		bit := (h1 + i*h2) % b.size
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if b.bits[word]&mask == 0 {
+			b.bits[word] |= mask
+			added = true
+		}
+	}
+
+	return added
+}
+
+func Dedup[I any](key func(item I) string, store DedupStore, sink SinkCtx[[]I]) SinkCtx[[]I] {
+	return func(ctx context.Context, items []I, stop func()) error {
+		unique := make([]I, 0, len(items))
+		for _, item := range items {
+			if store.Add(key(item)) {
+				unique = append(unique, item)
+			}
+		}
+
+		countDedup(ctx, len(unique), len(items)-len(unique))
+		return sink(ctx, unique, stop)
+	}
+}
+
+func DedupItem[I any](key func(item I) string, store DedupStore, sink ItemSinkCtx[I]) ItemSinkCtx[I] {
+	return func(ctx context.Context, item I, stop func()) error {
+		if !store.Add(key(item)) {
+			countDedup(ctx, 0, 1)
+			return nil
+		}
+
+		countDedup(ctx, 1, 0)
+		return sink(ctx, item, stop)
+	}
+}
+
+func countDedup(ctx context.Context, unique, duplicates int) {
+	scope, ok := ctx.Value(scopeKey{}).(*walkScope)
+	if !ok {
+		return
+	}
+
+	atomic.AddInt64(&scope.config.stats.uniqueItems, int64(unique))
+	atomic.AddInt64(&scope.config.stats.duplicates, int64(duplicates))
+}
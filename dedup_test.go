@@ -0,0 +1,140 @@
+package walker_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+func shiftingSource(start, fetchCount int) ([]int, error) {
+	first := start * (fetchCount - 2)
+	results := make([]int, 0, fetchCount)
+	for i := first; i < first+fetchCount && i < 100; i++ {
+		results = append(results, i+1)
+	}
+	return results, nil
+}
+
+func TestWalkerDeduplicatesOverlappingPages(t *testing.T) {
+	bloom, err := walker.NewBloomDedupStore(1000, 0.0001)
+	assert.Nil(t, err)
+	stores := map[string]walker.DedupStore{
+		"memory": walker.NewMemoryDedupStore(),
+		"bloom":  bloom,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			mockSink := MockSink{}
+			w := walker.NewCtx(
+				walker.AdaptSource(shiftingSource),
+				walker.Dedup(func(item int) string { return strconv.Itoa(item) }, store, walker.AdaptSink(mockSink.sink)),
+				walker.WithLimiter(walker.ConstantLimiter(130)),
+				walker.WithParallelism(1),
+			)
+			w.Walk()
+
+			seen := make([]int, 0)
+			for _, page := range mockSink.sortedResults() {
+				seen = append(seen, page...)
+			}
+
+			summary := w.Summary()
+			assert.Len(t, seen, 100)
+			assert.ElementsMatch(t, makeExpectedOutput(100, 100)[0], seen)
+			assert.Equal(t, int64(100), summary.UniqueItems)
+			assert.Equal(t, int64(24), summary.Duplicates)
+		})
+	}
+}
+
+func TestBloomDedupStoreFalsePositiveRate(t *testing.T) {
+	store, err := walker.NewBloomDedupStore(10000, 0.01)
+	assert.Nil(t, err)
+	for i := 0; i < 10000; i++ {
+		store.Add(fmt.Sprintf("item-%d", i))
+	}
+
+	falsePositives := 0
+	for i := 0; i < 1000; i++ {
+		if !store.Add(fmt.Sprintf("other-%d", i)) {
+			falsePositives++
+		}
+	}
+
+	assert.Less(t, falsePositives, 30)
+	assert.False(t, store.Add("item-42"))
+}
+
+func TestNewBloomDedupStoreRejectsInvalidInputs(t *testing.T) {
+	testCases := []struct {
+		expectedItems     int
+		falsePositiveRate float64
+		err               string
+	}{
+		{expectedItems: 0, falsePositiveRate: 0.01, err: "walker: bloom dedup store expected items must be positive, got 0"},
+		{expectedItems: 100, falsePositiveRate: 0, err: "walker: bloom dedup store false positive rate must be in (0, 1), got 0"},
+		{expectedItems: 100, falsePositiveRate: 1, err: "walker: bloom dedup store false positive rate must be in (0, 1), got 1"},
+		{expectedItems: 100, falsePositiveRate: math.NaN(), err: "walker: bloom dedup store false positive rate must be in (0, 1), got NaN"},
+	}
+
+	for _, tc := range testCases {
+		store, err := walker.NewBloomDedupStore(tc.expectedItems, tc.falsePositiveRate)
+		assert.Nil(t, store)
+		assert.EqualError(t, err, tc.err)
+	}
+}
+
+func TestApiWalkerDeduplicatesDecodedItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		items, _ := shiftingSource(start, 10)
+		json.NewEncoder(w).Encode(items)
+	}))
+	defer server.Close()
+
+	requestBuilder := func(start, fetchCount int) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, fmt.Sprintf("%s?start=%d", server.URL, start), http.NoBody)
+	}
+
+	var mutex sync.Mutex
+	seen := []int{}
+	dedup := walker.Dedup(func(item int) string { return strconv.Itoa(item) }, walker.NewMemoryDedupStore(), func(ctx context.Context, items []int, stop func()) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		seen = append(seen, items...)
+		return nil
+	})
+
+	sink := func(ctx context.Context, res *http.Response, stop func()) error {
+		defer res.Body.Close()
+		var items []int
+		if err := json.NewDecoder(res.Body).Decode(&items); err != nil {
+			return err
+		}
+		return dedup(ctx, items, stop)
+	}
+
+	w := walker.NewApiWalkerCtx(
+		http.DefaultClient,
+		walker.AdaptRequestBuilder(requestBuilder),
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(130)),
+		walker.WithParallelism(4),
+	)
+	w.Walk()
+
+	assert.Empty(t, w.FailedTasks())
+	assert.ElementsMatch(t, makeExpectedOutput(100, 100)[0], seen)
+	assert.Equal(t, int64(100), w.Summary().UniqueItems)
+	assert.Equal(t, int64(24), w.Summary().Duplicates)
+}
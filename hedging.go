@@ -0,0 +1,153 @@
+package walker
+
+import (
+	"context"
+	"math"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	latencyWindowSize        = 128
+	defaultHedgingMinSamples = 10
+)
+
+type hedging struct {
+	percentile float64
+	minSamples int
+}
+
+type latencyTracker struct {
+	mutex   sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+func (l *latencyTracker) record(latency time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if len(l.samples) < latencyWindowSize {
+		l.samples = append(l.samples, latency)
+		return
+	}
+
+	l.samples[l.next] = latency
+	l.next = (l.next + 1) % latencyWindowSize
+}
+
+func (l *latencyTracker) percentile(percentile float64, minSamples int) (time.Duration, bool) {
+	l.mutex.Lock()
+	sorted := append([]time.Duration{}, l.samples...)
+	l.mutex.Unlock()
+
+	if len(sorted) == 0 || len(sorted) < minSamples {
+		return 0, false
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(percentile/100*float64(len(sorted)))) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))], true
+}
+
+type attemptResult[T any] struct {
+	index    int
+	result   T
+	err      error
+	panicErr *PanicError
+}
+
+func (w *Walker[T]) fetchOnce(ctx context.Context, start, fetchCount int) (T, error) {
+	if w.hedging == nil {
+		atomic.AddInt64(&w.stats.sourceCalls, 1)
+		return w.source(ctx, start, fetchCount)
+	}
+	return w.fetchHedged(ctx, start, fetchCount)
+}
+
+func (w *Walker[T]) fetchHedged(ctx context.Context, start, fetchCount int) (T, error) {
+	results := make(chan attemptResult[T], 2)
+	cancels := make([]context.CancelFunc, 0, 2)
+	launch := func(hedged bool) {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+		go w.runAttempt(attemptCtx, len(cancels)-1, hedged, start, fetchCount, results)
+	}
+
+	launch(false)
+	pending := 1
+
+	var hedge <-chan time.Time
+	if threshold, ok := w.latencies.percentile(w.hedging.percentile, w.hedging.minSamples); ok {
+		timer := time.NewTimer(threshold)
+		defer timer.Stop()
+		hedge = timer.C
+	}
+
+	var winner attemptResult[T]
+	for pending > 0 {
+		select {
+		case winner = <-results:
+			pending--
+			if winner.err == nil && winner.panicErr == nil {
+				if pending > 0 {
+					go w.discardAttempts(results, pending)
+				}
+				pending = 0
+			}
+		case <-hedge:
+			hedge = nil
+			if w.budget.takeCall() {
+				launch(true)
+				pending++
+			}
+		}
+	}
+
+	for index, cancel := range cancels {
+		if index != winner.index {
+			cancel()
+		}
+	}
+
+	if winner.panicErr != nil {
+		panic(winner.panicErr)
+	}
+
+	return winner.result, winner.err
+}
+
+func (w *Walker[T]) discardAttempts(results <-chan attemptResult[T], pending int) {
+	for ; pending > 0; pending-- {
+		if attempt := <-results; attempt.err == nil && attempt.panicErr == nil && w.discard != nil {
+			w.discard(attempt.result)
+		}
+	}
+}
+
+func (w *Walker[T]) runAttempt(ctx context.Context, index int, hedged bool, start, fetchCount int, results chan<- attemptResult[T]) {
+	attempt := attemptResult[T]{index: index}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			attempt.panicErr = &PanicError{Value: recovered, Stack: debug.Stack()}
+		}
+		results <- attempt
+	}()
+
+	if hedged {
+		if attempt.err = w.rateLimiter.Wait(ctx); attempt.err != nil {
+			w.budget.refundCall()
+			return
+		}
+	}
+
+	atomic.AddInt64(&w.stats.sourceCalls, 1)
+	began := time.Now()
+	attempt.result, attempt.err = w.source(ctx, start, fetchCount)
+	if attempt.err == nil {
+		w.latencies.record(time.Since(began))
+	}
+}
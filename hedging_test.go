@@ -0,0 +1,111 @@
+package walker_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalkerHedgesSlowSourceCalls(t *testing.T) {
+	var slowCalls, cancelledCalls int32
+	source := func(ctx context.Context, start, fetchCount int) ([]int, error) {
+		if start == 150 && atomic.AddInt32(&slowCalls, 1) == 1 {
+			<-ctx.Done()
+			atomic.AddInt32(&cancelledCalls, 1)
+			return nil, ctx.Err()
+		}
+		time.Sleep(time.Millisecond)
+		return cursorSource(200)(start, fetchCount)
+	}
+
+	mockSink := MockSink{}
+	w := walker.NewCtx(
+		source,
+		walker.AdaptSink(mockSink.sink),
+		walker.WithLimiter(walker.ConstantLimiter(200)),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(1),
+		walker.WithHedging(90),
+	)
+
+	done := make(chan struct{})
+	go func() {
+		w.Walk()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("hedged request did not unblock the walk")
+	}
+
+	assert.Empty(t, w.FailedTasks())
+	assert.Equal(t, makeExpectedOutput(200, 10), mockSink.sortedResults())
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&cancelledCalls) == 1 }, time.Second, time.Millisecond)
+}
+
+func TestWalkerHedgingFallsBackToSlowerSuccessfulAttempt(t *testing.T) {
+	var calls int32
+	source := func(ctx context.Context, start, fetchCount int) ([]int, error) {
+		if start != 150 {
+			time.Sleep(time.Millisecond)
+			return cursorSource(200)(start, fetchCount)
+		}
+
+		if atomic.AddInt32(&calls, 1) == 1 {
+			time.Sleep(20 * time.Millisecond)
+			return cursorSource(200)(start, fetchCount)
+		}
+		return nil, context.Canceled
+	}
+
+	mockSink := MockSink{}
+	w := walker.NewCtx(
+		source,
+		walker.AdaptSink(mockSink.sink),
+		walker.WithLimiter(walker.ConstantLimiter(200)),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(1),
+		walker.WithHedging(50),
+	)
+	w.Walk()
+
+	assert.Empty(t, w.FailedTasks())
+	assert.Equal(t, makeExpectedOutput(200, 10), mockSink.sortedResults())
+}
+
+func TestWalkerHedgedCallsWaitForRateLimiter(t *testing.T) {
+	var calls int32
+	source := func(ctx context.Context, start, fetchCount int) ([]int, error) {
+		if atomic.AddInt32(&calls, 1) <= 10 {
+			return []int{start}, nil
+		}
+		select {
+		case <-time.After(20 * time.Millisecond):
+			return []int{start}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	limiter := &countingRateLimiter{}
+	w := walker.NewCtx(
+		source,
+		walker.AdaptSink((&MockSink{}).sink),
+		walker.WithLimiter(walker.ConstantLimiter(150)),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(1),
+		walker.WithHedging(50),
+		walker.WithRateLimiter(limiter),
+	)
+	w.Walk()
+
+	summary := w.Summary()
+	assert.Greater(t, summary.SourceCalls, int64(15))
+	assert.Equal(t, summary.SourceCalls, atomic.LoadInt64(&limiter.waits))
+}
@@ -0,0 +1,96 @@
+package walker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+type RequestKey func(req *http.Request) string
+
+func HostKey(req *http.Request) string {
+	return req.URL.Host
+}
+
+type hostLimit struct {
+	limiter RateLimiter
+	slots   chan struct{}
+}
+
+type hostLimits struct {
+	key         RequestKey
+	newLimiter  func(key string) RateLimiter
+	concurrency int
+	mutex       sync.Mutex
+	hosts       map[string]*hostLimit
+}
+
+func newHostLimits(c *config) *hostLimits {
+	if c.hostRateLimiter == nil && c.hostConcurrency <= 0 {
+		return nil
+	}
+
+	key := c.requestKey
+	if key == nil {
+		key = HostKey
+	}
+
+	return &hostLimits{
+		key:         key,
+		newLimiter:  c.hostRateLimiter,
+		concurrency: c.hostConcurrency,
+		hosts:       make(map[string]*hostLimit),
+	}
+}
+
+func (h *hostLimits) get(key string) *hostLimit {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if limit, ok := h.hosts[key]; ok {
+		return limit
+	}
+
+	limit := &hostLimit{limiter: UnlimitedRateLimiter()}
+	if h.newLimiter != nil {
+		limit.limiter = h.newLimiter(key)
+	}
+	if h.concurrency > 0 {
+		limit.slots = make(chan struct{}, h.concurrency)
+	}
+	h.hosts[key] = limit
+
+	return limit
+}
+
+func (h *hostLimits) acquire(ctx context.Context, req *http.Request) (func(), error) {
+	limit := h.get(h.key(req))
+
+	if err := limit.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	if limit.slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case limit.slots <- struct{}{}:
+		return func() { <-limit.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (r *releasingBody) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
@@ -0,0 +1,166 @@
+package walker_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+type concurrencyRecorder struct {
+	current int32
+	max     int32
+}
+
+func (c *concurrencyRecorder) enter() {
+	current := atomic.AddInt32(&c.current, 1)
+	for {
+		max := atomic.LoadInt32(&c.max)
+		if current <= max || atomic.CompareAndSwapInt32(&c.max, max, current) {
+			return
+		}
+	}
+}
+
+func (c *concurrencyRecorder) leave() {
+	atomic.AddInt32(&c.current, -1)
+}
+
+func newSlowServer(recorders ...*concurrencyRecorder) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, recorder := range recorders {
+			recorder.enter()
+			defer recorder.leave()
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+}
+
+func TestApiWalkerPerHostConcurrency(t *testing.T) {
+	var total, first, second concurrencyRecorder
+	firstServer, secondServer := newSlowServer(&total, &first), newSlowServer(&total, &second)
+	defer firstServer.Close()
+	defer secondServer.Close()
+
+	requestBuilder := func(start, fetchCount int) (*http.Request, error) {
+		server := firstServer
+		if start%2 == 1 {
+			server = secondServer
+		}
+		return http.NewRequest(http.MethodGet, server.URL, http.NoBody)
+	}
+
+	sink := func(res *http.Response, stop func()) error {
+		return res.Body.Close()
+	}
+
+	apiWalker := walker.NewApiWalker(
+		http.DefaultClient,
+		requestBuilder,
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(80)),
+		walker.WithParallelism(4),
+		walker.WithPerHostConcurrency(1),
+	)
+	apiWalker.Walk()
+
+	assert.Empty(t, apiWalker.FailedTasks())
+	assert.Equal(t, int32(1), first.max)
+	assert.Equal(t, int32(1), second.max)
+	assert.Equal(t, int32(2), total.max)
+}
+
+func TestApiWalkerPerKeyRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var mutex sync.Mutex
+	requestTimes := make(map[string][]time.Time)
+	limiters := make(map[string]int)
+	requestBuilder := func(start, fetchCount int) (*http.Request, error) {
+		shard := "even"
+		if start%2 == 1 {
+			shard = "odd"
+		}
+		req, err := http.NewRequest(http.MethodGet, server.URL, http.NoBody)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("X-Shard", shard)
+		return req, nil
+	}
+
+	sink := func(res *http.Response, stop func()) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		shard := res.Request.Header.Get("X-Shard")
+		requestTimes[shard] = append(requestTimes[shard], time.Now())
+		return res.Body.Close()
+	}
+
+	apiWalker := walker.NewApiWalker(
+		http.DefaultClient,
+		requestBuilder,
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(60)),
+		walker.WithParallelism(2),
+		walker.WithRequestKey(func(req *http.Request) string { return req.Header.Get("X-Shard") }),
+		walker.WithPerHostRateLimiter(func(key string) walker.RateLimiter {
+			mutex.Lock()
+			defer mutex.Unlock()
+			limiters[key]++
+			return walker.NewSlidingWindow(1, 30*time.Millisecond)
+		}),
+	)
+	apiWalker.Walk()
+
+	assert.Equal(t, map[string]int{"even": 1, "odd": 1}, limiters)
+	for _, shard := range []string{"even", "odd"} {
+		times := requestTimes[shard]
+		if assert.Len(t, times, 3) {
+			assert.GreaterOrEqual(t, times[2].Sub(times[0]), 50*time.Millisecond)
+		}
+	}
+}
+
+func TestApiWalkerPerHostConcurrencyHoldsSlotUntilBodyClosed(t *testing.T) {
+	var recorder concurrencyRecorder
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte("body"))
+	}))
+	defer server.Close()
+
+	requestBuilder := func(start, fetchCount int) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, server.URL, http.NoBody)
+	}
+
+	sink := func(res *http.Response, stop func()) error {
+		recorder.enter()
+		defer recorder.leave()
+		defer res.Body.Close()
+		_, err := io.ReadAll(res.Body)
+		return err
+	}
+
+	apiWalker := walker.NewApiWalker(
+		http.DefaultClient,
+		requestBuilder,
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(40)),
+		walker.WithParallelism(4),
+		walker.WithPerHostConcurrency(1),
+	)
+	apiWalker.Walk()
+
+	assert.Empty(t, apiWalker.FailedTasks())
+	assert.Equal(t, int32(1), recorder.max)
+}
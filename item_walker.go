@@ -0,0 +1,74 @@
+package walker
+
+import "context"
+
+type ItemSink[I any] func(item I, stop func()) error
+type ItemSinkCtx[I any] func(ctx context.Context, item I, stop func()) error
+type Extractor[P, I any] func(page P) ([]I, error)
+
+func NewItemWalker[P, I any](source Source[P], extract Extractor[P, I], sink ItemSink[I], options ...Option) *Walker[P] {
+	itemSink := func(_ context.Context, item I, stop func()) error {
+		return sink(item, stop)
+	}
+	return NewItemWalkerCtx(AdaptSource(source), extract, itemSink, options...)
+}
+
+func NewItemWalkerCtx[P, I any](source SourceCtx[P], extract Extractor[P, I], sink ItemSinkCtx[I], options ...Option) *Walker[P] {
+	return newItemWalker(source, extract, sink, newConfig(options...))
+}
+
+func NewItemWalkerE[P, I any](source Source[P], extract Extractor[P, I], sink ItemSink[I], options ...Option) (*Walker[P], error) {
+	var sourceCtx SourceCtx[P]
+	if source != nil {
+		sourceCtx = AdaptSource(source)
+	}
+	var itemSink ItemSinkCtx[I]
+	if sink != nil {
+		itemSink = func(_ context.Context, item I, stop func()) error {
+			return sink(item, stop)
+		}
+	}
+	return NewItemWalkerCtxE(sourceCtx, extract, itemSink, options...)
+}
+
+func NewItemWalkerCtxE[P, I any](source SourceCtx[P], extract Extractor[P, I], sink ItemSinkCtx[I], options ...Option) (*Walker[P], error) {
+	errs := ValidationError{}
+	if source == nil {
+		errs = append(errs, ErrNilSource)
+	}
+	if extract == nil {
+		errs = append(errs, ErrNilExtractor)
+	}
+	if sink == nil {
+		errs = append(errs, ErrNilSink)
+	}
+
+	config, err := newValidConfig(errs, options...)
+	if err != nil {
+		return nil, err
+	}
+	return newItemWalker(source, extract, sink, config), nil
+}
+
+func newItemWalker[P, I any](source SourceCtx[P], extract Extractor[P, I], sink ItemSinkCtx[I], config *config) *Walker[P] {
+	var walker *Walker[P]
+	pageSink := func(ctx context.Context, page P, stop func()) error {
+		items, err := extract(page)
+		if err != nil {
+			return err
+		}
+
+		task, _ := TaskFromContext(ctx)
+		for index, item := range items {
+			if err := sink(ctx, item, stop); err != nil {
+				walker.storeFailedItem(task.Start, task.FetchCount, index, err)
+			}
+		}
+
+		return nil
+	}
+
+	walker = newWalker(source, pageSink, config)
+	walker.skipFailedPages = true
+	return walker
+}
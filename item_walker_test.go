@@ -0,0 +1,146 @@
+package walker_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+type page struct {
+	Items []int
+}
+
+func pageSource(start, fetchCount int) (page, error) {
+	items, err := cursorSource(50)(start, fetchCount)
+	return page{Items: items}, err
+}
+
+func extractItems(p page) ([]int, error) {
+	return p.Items, nil
+}
+
+type itemCollector struct {
+	sync.Mutex
+	items []int
+}
+
+func (c *itemCollector) sink(item int, stop func()) error {
+	c.Lock()
+	defer c.Unlock()
+	c.items = append(c.items, item)
+	return nil
+}
+
+func (c *itemCollector) sinkCtx(ctx context.Context, item int, stop func()) error {
+	return c.sink(item, stop)
+}
+
+func TestItemWalkerFlattensPages(t *testing.T) {
+	collector := &itemCollector{}
+	w := walker.NewItemWalker(
+		pageSource,
+		extractItems,
+		collector.sink,
+		walker.WithLimiter(walker.ConstantLimiter(50)),
+		walker.WithPagination(walker.CursorPagination{}),
+	)
+	w.Walk()
+
+	assert.Empty(t, w.FailedTasks())
+	assert.ElementsMatch(t, makeExpectedOutput(50, 50)[0], collector.items)
+}
+
+func TestItemWalkerRecordsFailedItems(t *testing.T) {
+	errOdd := errors.New("odd item")
+	sink := func(item int, stop func()) error {
+		if item == 13 {
+			return errOdd
+		}
+		return nil
+	}
+
+	w := walker.NewItemWalker(
+		pageSource,
+		extractItems,
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(50)),
+		walker.WithPagination(walker.CursorPagination{}),
+	)
+	w.Walk()
+
+	assert.Equal(t, []walker.FailedTask{{Start: 10, FetchCount: 10, ItemIndex: 2, Err: errOdd}}, w.FailedTasks())
+}
+
+func TestItemWalkerSkipsExtractionOfFailedPages(t *testing.T) {
+	errSource := errors.New("source failure")
+	source := func(start, fetchCount int) (*page, error) {
+		if start == 20 {
+			return nil, errSource
+		}
+		p, err := pageSource(start, fetchCount)
+		return &p, err
+	}
+	extract := func(p *page) ([]int, error) {
+		return p.Items, nil
+	}
+
+	collector := &itemCollector{}
+	w := walker.NewItemWalker(
+		source,
+		extract,
+		collector.sink,
+		walker.WithLimiter(walker.ConstantLimiter(50)),
+		walker.WithPagination(walker.CursorPagination{}),
+	)
+	w.Walk()
+
+	assert.Equal(t, []walker.FailedTask{{Start: 20, FetchCount: 10, ItemIndex: walker.NoItemIndex, Err: errSource}}, w.FailedTasks())
+	assert.Len(t, collector.items, 40)
+}
+
+func TestItemWalkerRecordsExtractorFailures(t *testing.T) {
+	errExtract := errors.New("malformed page")
+	extract := func(p page) ([]int, error) {
+		if p.Items[0] == 21 {
+			return nil, errExtract
+		}
+		return p.Items, nil
+	}
+
+	collector := &itemCollector{}
+	w := walker.NewItemWalker(
+		pageSource,
+		extract,
+		collector.sink,
+		walker.WithLimiter(walker.ConstantLimiter(50)),
+		walker.WithPagination(walker.CursorPagination{}),
+	)
+	w.Walk()
+
+	assert.Equal(t, []walker.FailedTask{{Start: 20, FetchCount: 10, ItemIndex: walker.NoItemIndex, Err: errExtract}}, w.FailedTasks())
+	assert.Len(t, collector.items, 40)
+}
+
+func TestItemWalkerDeduplicatesItems(t *testing.T) {
+	source := func(start, fetchCount int) (page, error) {
+		items, err := shiftingSource(start, fetchCount)
+		return page{Items: items}, err
+	}
+
+	collector := &itemCollector{}
+	w := walker.NewItemWalkerCtx(
+		walker.AdaptSource(source),
+		extractItems,
+		walker.DedupItem(func(item int) string { return strconv.Itoa(item) }, walker.NewMemoryDedupStore(), collector.sinkCtx),
+		walker.WithLimiter(walker.ConstantLimiter(130)),
+	)
+	w.Walk()
+
+	assert.Len(t, collector.items, 100)
+	assert.Equal(t, int64(24), w.Summary().Duplicates)
+}
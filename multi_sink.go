@@ -0,0 +1,137 @@
+package walker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type FailurePolicy int
+
+const (
+	FailOnAnySink FailurePolicy = iota
+	FailOnAllSinks
+	IgnoreSinkFailures
+)
+
+type MultiSinkConfig struct {
+	Sequential    bool
+	FailurePolicy FailurePolicy
+}
+
+type SinkError struct {
+	Index int
+	Err   error
+}
+
+func (s *SinkError) Error() string {
+	return fmt.Sprintf("walker: sink %d failed: %v", s.Index, s.Err)
+}
+
+func (s *SinkError) Unwrap() error {
+	return s.Err
+}
+
+type MultiSinkError []*SinkError
+
+func (m MultiSinkError) Error() string {
+	messages := make([]string, 0, len(m))
+	for _, err := range m {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (m MultiSinkError) Is(target error) bool {
+	for _, err := range m {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m MultiSinkError) As(target any) bool {
+	for _, err := range m {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+type MultiSink[T any] struct {
+	sinks    []SinkCtx[T]
+	config   MultiSinkConfig
+	failures []int64
+}
+
+func NewMultiSink[T any](config MultiSinkConfig, sinks ...SinkCtx[T]) *MultiSink[T] {
+	return &MultiSink[T]{sinks: sinks, config: config, failures: make([]int64, len(sinks))}
+}
+
+func (m *MultiSink[T]) Sink(ctx context.Context, result T, stop func()) error {
+	errs := make([]error, len(m.sinks))
+	if m.config.Sequential {
+		for index, sink := range m.sinks {
+			errs[index] = sink(ctx, result, stop)
+		}
+	} else {
+		m.deliverConcurrently(ctx, result, stop, errs)
+	}
+
+	failed := MultiSinkError{}
+	for index, err := range errs {
+		if err != nil {
+			atomic.AddInt64(&m.failures[index], 1)
+			failed = append(failed, &SinkError{Index: index, Err: err})
+		}
+	}
+
+	switch {
+	case len(failed) == 0 || m.config.FailurePolicy == IgnoreSinkFailures:
+		return nil
+	case m.config.FailurePolicy == FailOnAllSinks && len(failed) < len(m.sinks):
+		return nil
+	}
+	return failed
+}
+
+func (m *MultiSink[T]) deliverConcurrently(ctx context.Context, result T, stop func(), errs []error) {
+	var wg sync.WaitGroup
+	var panicErr *PanicError
+	var panicOnce sync.Once
+
+	for index, sink := range m.sinks {
+		wg.Add(1)
+		go func(index int, sink SinkCtx[T]) {
+			defer wg.Done()
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					panicOnce.Do(func() {
+						panicErr = &PanicError{Value: recovered, Stack: debug.Stack()}
+					})
+				}
+			}()
+
+			errs[index] = sink(ctx, result, stop)
+		}(index, sink)
+	}
+	wg.Wait()
+
+	if panicErr != nil {
+		panic(panicErr)
+	}
+}
+
+func (m *MultiSink[T]) Failures() []int64 {
+	failures := make([]int64, len(m.failures))
+	for index := range m.failures {
+		failures[index] = atomic.LoadInt64(&m.failures[index])
+	}
+	return failures
+}
@@ -0,0 +1,131 @@
+package walker_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingSink struct {
+	calls  int64
+	failOn int
+}
+
+func (c *countingSink) sink(ctx context.Context, result int, stop func()) error {
+	atomic.AddInt64(&c.calls, 1)
+	if result == c.failOn {
+		return errors.New("sink failed")
+	}
+	return nil
+}
+
+func TestMultiSinkDeliversToEverySink(t *testing.T) {
+	first, second := &countingSink{failOn: -1}, &countingSink{failOn: -1}
+	multi := walker.NewMultiSink(walker.MultiSinkConfig{}, first.sink, second.sink)
+
+	w := walker.NewCtx(
+		func(ctx context.Context, start, fetchCount int) (int, error) { return start, nil },
+		multi.Sink,
+		walker.WithLimiter(walker.ConstantLimiter(100)),
+	)
+	w.Walk()
+
+	assert.Equal(t, int64(10), first.calls)
+	assert.Equal(t, int64(10), second.calls)
+	assert.Empty(t, w.FailedTasks())
+	assert.Equal(t, []int64{0, 0}, multi.Failures())
+}
+
+func TestMultiSinkFailurePolicies(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy walker.FailurePolicy
+		failed []int
+	}{
+		{name: "any", policy: walker.FailOnAnySink, failed: []int{3, 5}},
+		{name: "all", policy: walker.FailOnAllSinks, failed: []int{3}},
+		{name: "ignore", policy: walker.IgnoreSinkFailures, failed: []int{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			first := &countingSink{failOn: 3}
+			second := func(ctx context.Context, result int, stop func()) error {
+				if result == 3 || result == 5 {
+					return errors.New("sink failed")
+				}
+				return nil
+			}
+			multi := walker.NewMultiSink(walker.MultiSinkConfig{FailurePolicy: tc.policy}, first.sink, second)
+
+			w := walker.NewCtx(
+				func(ctx context.Context, start, fetchCount int) (int, error) { return start, nil },
+				multi.Sink,
+				walker.WithLimiter(walker.ConstantLimiter(100)),
+			)
+			w.Walk()
+
+			failed := []int{}
+			for _, task := range w.FailedTasks() {
+				var multiErr walker.MultiSinkError
+				assert.ErrorAs(t, task.Err, &multiErr)
+				failed = append(failed, task.Start)
+			}
+			assert.ElementsMatch(t, tc.failed, failed)
+			assert.Equal(t, []int64{1, 2}, multi.Failures())
+		})
+	}
+}
+
+func TestMultiSinkSequentialPreservesOrder(t *testing.T) {
+	var mutex sync.Mutex
+	order := []string{}
+	record := func(name string, delay time.Duration) walker.SinkCtx[int] {
+		return func(ctx context.Context, result int, stop func()) error {
+			time.Sleep(delay)
+			mutex.Lock()
+			defer mutex.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	multi := walker.NewMultiSink(walker.MultiSinkConfig{Sequential: true}, record("slow", 10*time.Millisecond), record("fast", 0))
+	err := multi.Sink(context.Background(), 1, func() {})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"slow", "fast"}, order)
+}
+
+func TestMultiSinkReportsWhichSinkFailed(t *testing.T) {
+	failing := &countingSink{failOn: 1}
+	multi := walker.NewMultiSink(walker.MultiSinkConfig{}, (&countingSink{failOn: -1}).sink, failing.sink)
+
+	err := multi.Sink(context.Background(), 1, func() {})
+
+	var sinkErr *walker.SinkError
+	assert.ErrorAs(t, err, &sinkErr)
+	assert.Equal(t, 1, sinkErr.Index)
+	assert.EqualError(t, err, "walker: sink 1 failed: sink failed")
+}
+
+func TestMultiSinkErrorMatchesErrorsOfFailedSinks(t *testing.T) {
+	errFull := errors.New("disk full")
+	full := func(ctx context.Context, result int, stop func()) error { return errFull }
+	multi := walker.NewMultiSink(walker.MultiSinkConfig{Sequential: true}, (&countingSink{failOn: 1}).sink, full)
+
+	err := multi.Sink(context.Background(), 1, func() {})
+
+	assert.ErrorIs(t, err, errFull)
+	assert.NotErrorIs(t, err, context.Canceled)
+
+	var sinkErr *walker.SinkError
+	assert.ErrorAs(t, err, &sinkErr)
+	assert.Equal(t, 0, sinkErr.Index)
+}
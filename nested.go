@@ -0,0 +1,112 @@
+package walker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+var (
+	ErrNoParentWalk    = errors.New("walker: context does not belong to a walk")
+	ErrBudgetExhausted = errors.New("walker: budget exhausted")
+)
+
+type scopeKey struct{}
+
+type SpawnedWalkError struct {
+	Spawner Task
+	Err     error
+}
+
+func (s *SpawnedWalkError) Error() string {
+	return fmt.Sprintf("walker: walk spawned by task start=%d count=%d failed: %v", s.Spawner.Start, s.Spawner.FetchCount, s.Err)
+}
+
+func (s *SpawnedWalkError) Unwrap() error {
+	return s.Err
+}
+
+type walkScope struct {
+	context         context.Context
+	rateLimiter     RateLimiter
+	config          *config
+	storeFailedItem func(start, fetchCount, itemIndex int, err error)
+	isStopped       func() bool
+	spawn           func(walk func())
+}
+
+func newWalkScope[T any](w *Walker[T]) *walkScope {
+	return &walkScope{
+		context:         w.context,
+		rateLimiter:     w.rateLimiter,
+		config:          w.config,
+		storeFailedItem: w.storeFailedItem,
+		isStopped:       w.IsStopped,
+		spawn: func(walk func()) {
+			w.children.Add(1)
+			go func() {
+				defer w.children.Done()
+				defer func() {
+					if recovered := recover(); recovered != nil {
+						panicErr, ok := recovered.(*PanicError)
+						if !ok {
+							panic(recovered)
+						}
+						w.Stop()
+						w.panicOnce.Do(func() {
+							w.panicErr = panicErr
+						})
+					}
+				}()
+				walk()
+			}()
+		},
+	}
+}
+
+func Fetch[R any](ctx context.Context, fetch func(ctx context.Context) (R, error)) (R, error) {
+	scope, ok := ctx.Value(scopeKey{}).(*walkScope)
+	if !ok {
+		return fetch(ctx)
+	}
+
+	var zero R
+	if err := scope.rateLimiter.Wait(ctx); err != nil {
+		return zero, err
+	}
+	if !scope.config.budget.takeCall() {
+		return zero, ErrBudgetExhausted
+	}
+
+	atomic.AddInt64(&scope.config.stats.sourceCalls, 1)
+	return fetch(ctx)
+}
+
+func Spawn[C any](ctx context.Context, child *Walker[C]) error {
+	scope, ok := ctx.Value(scopeKey{}).(*walkScope)
+	if !ok {
+		return ErrNoParentWalk
+	}
+
+	spawner, _ := TaskFromContext(ctx)
+	child.adopt(scope, spawner)
+	scope.spawn(child.Walk)
+	return nil
+}
+
+func (w *Walker[T]) adopt(parent *walkScope, spawner Task) {
+	w.sourcePool.StopAndWait()
+	w.sinkPool.StopAndWait()
+	w.contextCancel()
+
+	WithContext(parent.context)(w.config)
+	w.sourcePool = newSourcePool(w.config)
+	w.sinkPool = newSinkPool(w.config)
+	w.rateLimiter = CombineRateLimiters(parent.rateLimiter, w.rateLimiter)
+	w.budget = parent.config.budget
+	w.stats = parent.config.stats
+	w.parent = parent
+	w.spawner = spawner
+	w.scope = newWalkScope(w)
+}
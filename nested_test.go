@@ -0,0 +1,131 @@
+package walker_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingRateLimiter struct {
+	waits int64
+}
+
+func (c *countingRateLimiter) Wait(ctx context.Context) error {
+	atomic.AddInt64(&c.waits, 1)
+	return nil
+}
+
+func TestSpawnedWalksFinishBeforeParentWalkReturns(t *testing.T) {
+	var mutex sync.Mutex
+	details := []int{}
+	limiter := &countingRateLimiter{}
+
+	detailSink := func(ctx context.Context, ids []int, stop func()) error {
+		time.Sleep(5 * time.Millisecond)
+		mutex.Lock()
+		defer mutex.Unlock()
+		details = append(details, ids...)
+		return nil
+	}
+
+	listSink := func(ctx context.Context, ids []int, stop func()) error {
+		child := walker.NewCtx(
+			func(ctx context.Context, start, fetchCount int) ([]int, error) {
+				return ids[start : start+fetchCount], nil
+			},
+			detailSink,
+			walker.WithLimiter(walker.ConstantLimiter(len(ids))),
+			walker.WithMaxBatchSize(1),
+			walker.WithPagination(walker.CursorPagination{}),
+		)
+		return walker.Spawn(ctx, child)
+	}
+
+	w := walker.NewCtx(
+		pageOfIDs,
+		listSink,
+		walker.WithLimiter(walker.ConstantLimiter(20)),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithRateLimiter(limiter),
+	)
+	w.Walk()
+
+	assert.Len(t, details, 20)
+	assert.Equal(t, int64(22), w.Summary().SourceCalls)
+	assert.Equal(t, int64(22), limiter.waits)
+}
+
+func TestSpawnedWalkFailuresAreTrackedByParent(t *testing.T) {
+	listSink := func(ctx context.Context, ids []int, stop func()) error {
+		child := walker.NewCtx(
+			func(ctx context.Context, start, fetchCount int) ([]int, error) {
+				return nil, errors.New("detail failed")
+			},
+			func(ctx context.Context, ids []int, stop func()) error { return nil },
+			walker.WithLimiter(walker.ConstantLimiter(1)),
+		)
+		return walker.Spawn(ctx, child)
+	}
+
+	w := walker.NewCtx(pageOfIDs, listSink, walker.WithLimiter(walker.ConstantLimiter(10)), walker.WithMaxBatchSize(5))
+	w.Walk()
+
+	failed := w.FailedTasks()
+	assert.Len(t, failed, 2)
+	sort.Slice(failed, func(i, j int) bool { return failed[i].Err.Error() < failed[j].Err.Error() })
+
+	var spawnedErr *walker.SpawnedWalkError
+	assert.ErrorAs(t, failed[0].Err, &spawnedErr)
+	assert.Equal(t, walker.Task{Start: 0, FetchCount: 5}, spawnedErr.Spawner)
+	assert.EqualError(t, failed[0].Err, "walker: walk spawned by task start=0 count=5 failed: detail failed")
+	assert.EqualError(t, failed[1].Err, "walker: walk spawned by task start=1 count=5 failed: detail failed")
+}
+
+func TestFetchSharesRateLimiterAndBudget(t *testing.T) {
+	limiter := &countingRateLimiter{}
+	var fetched, exhausted int64
+
+	sink := func(ctx context.Context, ids []int, stop func()) error {
+		for _, id := range ids {
+			_, err := walker.Fetch(ctx, func(ctx context.Context) (int, error) { return id * 2, nil })
+			if errors.Is(err, walker.ErrBudgetExhausted) {
+				atomic.AddInt64(&exhausted, 1)
+				continue
+			}
+			atomic.AddInt64(&fetched, 1)
+		}
+		return nil
+	}
+
+	w := walker.NewCtx(
+		pageOfIDs,
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(5)),
+		walker.WithMaxBatchSize(5),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithRateLimiter(limiter),
+		walker.WithBudget(4),
+	)
+	w.Walk()
+
+	assert.Equal(t, int64(3), fetched)
+	assert.Equal(t, int64(2), exhausted)
+	assert.Equal(t, int64(6), limiter.waits)
+	assert.Equal(t, int64(4), w.Summary().SourceCalls)
+}
+
+func TestSpawnOutsideOfWalk(t *testing.T) {
+	child := walker.NewCtx(pageOfIDs, func(ctx context.Context, ids []int, stop func()) error { return nil })
+	assert.ErrorIs(t, walker.Spawn(context.Background(), child), walker.ErrNoParentWalk)
+
+	value, err := walker.Fetch(context.Background(), func(ctx context.Context) (int, error) { return 1, nil })
+	assert.Nil(t, err)
+	assert.Equal(t, 1, value)
+}
@@ -0,0 +1,234 @@
+package walker
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+)
+
+type Walkable interface {
+	Walk()
+	Summary() Summary
+}
+
+type Outputs map[string]any
+
+func OutputOf[O any](outputs Outputs, step string) []O {
+	output, _ := outputs[step].([]O)
+	return output
+}
+
+type Step struct {
+	name      string
+	dependsOn []string
+	run       func(ctx context.Context, inputs Outputs) (any, Summary, error)
+}
+
+func NewStep[O any](name string, dependsOn []string, build func(ctx context.Context, inputs Outputs, emit func(O)) (Walkable, error)) Step {
+	return Step{
+		name:      name,
+		dependsOn: dependsOn,
+		run: func(ctx context.Context, inputs Outputs) (any, Summary, error) {
+			var mutex sync.Mutex
+			output := make([]O, 0)
+			emit := func(value O) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				output = append(output, value)
+			}
+
+			walkable, err := build(ctx, inputs, emit)
+			if err != nil {
+				return nil, Summary{}, err
+			}
+			walkable.Walk()
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			return output, walkable.Summary(), nil
+		},
+	}
+}
+
+type OrchestratorConfig struct {
+	MaxConcurrentSteps int
+	Context            context.Context
+}
+
+type StepError struct {
+	Step string
+	Err  error
+}
+
+func (s *StepError) Error() string {
+	return fmt.Sprintf("walker: step %q failed: %v", s.Step, s.Err)
+}
+
+func (s *StepError) Unwrap() error {
+	return s.Err
+}
+
+type OrchestrationSummary struct {
+	Steps   map[string]Summary
+	Total   Summary
+	Errors  map[string]error
+	Skipped []string
+}
+
+type Orchestrator struct {
+	steps  []Step
+	config OrchestratorConfig
+}
+
+func NewOrchestrator(config OrchestratorConfig, steps ...Step) *Orchestrator {
+	if config.MaxConcurrentSteps <= 0 {
+		config.MaxConcurrentSteps = len(steps)
+	}
+	if config.Context == nil {
+		config.Context = context.Background()
+	}
+	return &Orchestrator{steps: steps, config: config}
+}
+
+func (o *Orchestrator) Validate() error {
+	steps := make(map[string]Step, len(o.steps))
+	for _, step := range o.steps {
+		if _, ok := steps[step.name]; ok {
+			return fmt.Errorf("walker: duplicate step %q", step.name)
+		}
+		steps[step.name] = step
+	}
+
+	for _, step := range o.steps {
+		for _, dependency := range step.dependsOn {
+			if _, ok := steps[dependency]; !ok {
+				return fmt.Errorf("walker: step %q depends on unknown step %q", step.name, dependency)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := make(map[string]int, len(o.steps))
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch states[name] {
+		case visiting:
+			return fmt.Errorf("walker: dependency cycle through step %q", name)
+		case visited:
+			return nil
+		}
+
+		states[name] = visiting
+		for _, dependency := range steps[name].dependsOn {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		states[name] = visited
+		return nil
+	}
+
+	for _, step := range o.steps {
+		if err := visit(step.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *Orchestrator) Run() (OrchestrationSummary, error) {
+	summary := OrchestrationSummary{Steps: map[string]Summary{}, Errors: map[string]error{}, Skipped: []string{}}
+	if err := o.Validate(); err != nil {
+		return summary, err
+	}
+
+	var mutex sync.Mutex
+	outputs := Outputs{}
+	done := make(map[string]chan struct{}, len(o.steps))
+	for _, step := range o.steps {
+		done[step.name] = make(chan struct{})
+	}
+
+	slots := make(chan struct{}, o.config.MaxConcurrentSteps)
+	var wg sync.WaitGroup
+	for _, step := range o.steps {
+		wg.Add(1)
+		go func(step Step) {
+			defer wg.Done()
+			defer close(done[step.name])
+
+			for _, dependency := range step.dependsOn {
+				<-done[dependency]
+			}
+
+			mutex.Lock()
+			inputs := Outputs{}
+			runnable := true
+			for _, dependency := range step.dependsOn {
+				output, ok := outputs[dependency]
+				runnable = runnable && ok
+				inputs[dependency] = output
+			}
+			mutex.Unlock()
+
+			if !runnable || o.config.Context.Err() != nil {
+				mutex.Lock()
+				summary.Skipped = append(summary.Skipped, step.name)
+				mutex.Unlock()
+				return
+			}
+
+			slots <- struct{}{}
+			output, stepSummary, err := o.runStep(step, inputs)
+			<-slots
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				summary.Errors[step.name] = &StepError{Step: step.name, Err: err}
+				return
+			}
+			outputs[step.name] = output
+			summary.Steps[step.name] = stepSummary
+		}(step)
+	}
+	wg.Wait()
+
+	sort.Strings(summary.Skipped)
+	summary.Total = Summary{}
+	for _, step := range o.steps {
+		if stepSummary, ok := summary.Steps[step.name]; ok {
+			summary.Total = summary.Total.add(stepSummary)
+		}
+	}
+	if len(summary.Steps) == 0 {
+		summary.Total.RemainingCalls, summary.Total.RemainingBytes = Unlimited, Unlimited
+	}
+
+	for _, step := range o.steps {
+		if err, ok := summary.Errors[step.name]; ok {
+			return summary, err
+		}
+	}
+	return summary, nil
+}
+
+func (o *Orchestrator) runStep(step Step, inputs Outputs) (output any, summary Summary, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			panicErr, ok := recovered.(*PanicError)
+			if !ok {
+				panicErr = &PanicError{Value: recovered, Stack: debug.Stack()}
+			}
+			err = panicErr
+		}
+	}()
+
+	return step.run(o.config.Context, inputs)
+}
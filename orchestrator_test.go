@@ -0,0 +1,145 @@
+package walker_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+func idWalker(limit int, emit func(int)) *walker.Walker[[]int] {
+	return walker.NewCtx(
+		pageOfIDs,
+		func(ctx context.Context, ids []int, stop func()) error {
+			for _, id := range ids {
+				emit(id)
+			}
+			return nil
+		},
+		walker.WithLimiter(walker.ConstantLimiter(limit)),
+		walker.WithPagination(walker.CursorPagination{}),
+	)
+}
+
+func TestOrchestratorPassesOutputsDownstream(t *testing.T) {
+	users := walker.NewStep("users", nil, func(ctx context.Context, inputs walker.Outputs, emit func(int)) (walker.Walkable, error) {
+		return idWalker(20, emit), nil
+	})
+	repos := walker.NewStep("repos", []string{"users"}, func(ctx context.Context, inputs walker.Outputs, emit func(int)) (walker.Walkable, error) {
+		userIDs := walker.OutputOf[int](inputs, "users")
+		return idWalker(len(userIDs)*2, emit), nil
+	})
+	issues := walker.NewStep("issues", []string{"users", "repos"}, func(ctx context.Context, inputs walker.Outputs, emit func(string)) (walker.Walkable, error) {
+		assert.Len(t, walker.OutputOf[int](inputs, "repos"), 40)
+		return idWalker(5, func(id int) { emit("issue") }), nil
+	})
+
+	orchestrator := walker.NewOrchestrator(walker.OrchestratorConfig{}, issues, repos, users)
+	summary, err := orchestrator.Run()
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), summary.Steps["users"].SourceCalls)
+	assert.Equal(t, int64(4), summary.Steps["repos"].SourceCalls)
+	assert.Equal(t, int64(1), summary.Steps["issues"].SourceCalls)
+	assert.Equal(t, int64(7), summary.Total.SourceCalls)
+	assert.Equal(t, int64(walker.Unlimited), summary.Total.RemainingCalls)
+	assert.Empty(t, summary.Skipped)
+}
+
+func TestOrchestratorTotalIsUnlimitedWhenAnyStepIsUnlimited(t *testing.T) {
+	newStep := func(name string, options ...walker.Option) walker.Step {
+		return walker.NewStep(name, nil, func(ctx context.Context, inputs walker.Outputs, emit func(int)) (walker.Walkable, error) {
+			return walker.NewCtx(pageOfIDs, func(ctx context.Context, ids []int, stop func()) error { return nil }, append(options, walker.WithLimiter(walker.ConstantLimiter(10)))...), nil
+		})
+	}
+
+	summary, err := walker.NewOrchestrator(walker.OrchestratorConfig{}, newStep("a", walker.WithBudget(100)), newStep("b")).Run()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(99), summary.Steps["a"].RemainingCalls)
+	assert.Equal(t, int64(walker.Unlimited), summary.Total.RemainingCalls)
+
+	summary, err = walker.NewOrchestrator(walker.OrchestratorConfig{}, newStep("a", walker.WithBudget(100)), newStep("b", walker.WithBudget(10))).Run()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(108), summary.Total.RemainingCalls)
+}
+
+func TestOrchestratorLimitsConcurrentSteps(t *testing.T) {
+	var running, peak int64
+	steps := []walker.Step{}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		steps = append(steps, walker.NewStep(name, nil, func(ctx context.Context, inputs walker.Outputs, emit func(int)) (walker.Walkable, error) {
+			return walker.NewCtx(
+				func(ctx context.Context, start, fetchCount int) (int, error) {
+					current := atomic.AddInt64(&running, 1)
+					defer atomic.AddInt64(&running, -1)
+					if current > atomic.LoadInt64(&peak) {
+						atomic.StoreInt64(&peak, current)
+					}
+					time.Sleep(10 * time.Millisecond)
+					return start, nil
+				},
+				func(ctx context.Context, result int, stop func()) error { return nil },
+				walker.WithLimiter(walker.ConstantLimiter(1)),
+			), nil
+		}))
+	}
+
+	_, err := walker.NewOrchestrator(walker.OrchestratorConfig{MaxConcurrentSteps: 2}, steps...).Run()
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&peak))
+}
+
+func TestOrchestratorSkipsDependentsOfFailedStep(t *testing.T) {
+	failing := walker.NewStep("users", nil, func(ctx context.Context, inputs walker.Outputs, emit func(int)) (walker.Walkable, error) {
+		return nil, errors.New("no credentials")
+	})
+	independent := walker.NewStep("teams", nil, func(ctx context.Context, inputs walker.Outputs, emit func(int)) (walker.Walkable, error) {
+		return idWalker(10, emit), nil
+	})
+	repos := walker.NewStep("repos", []string{"users"}, func(ctx context.Context, inputs walker.Outputs, emit func(int)) (walker.Walkable, error) {
+		t.Error("dependent of a failed step must not run")
+		return idWalker(10, emit), nil
+	})
+	issues := walker.NewStep("issues", []string{"repos"}, func(ctx context.Context, inputs walker.Outputs, emit func(int)) (walker.Walkable, error) {
+		t.Error("transitive dependent of a failed step must not run")
+		return idWalker(10, emit), nil
+	})
+
+	summary, err := walker.NewOrchestrator(walker.OrchestratorConfig{}, failing, independent, repos, issues).Run()
+
+	var stepErr *walker.StepError
+	assert.ErrorAs(t, err, &stepErr)
+	assert.Equal(t, "users", stepErr.Step)
+	assert.Equal(t, []string{"issues", "repos"}, summary.Skipped)
+	assert.Contains(t, summary.Steps, "teams")
+}
+
+func TestOrchestratorValidatesGraph(t *testing.T) {
+	step := func(name string, dependsOn ...string) walker.Step {
+		return walker.NewStep(name, dependsOn, func(ctx context.Context, inputs walker.Outputs, emit func(int)) (walker.Walkable, error) {
+			return idWalker(1, emit), nil
+		})
+	}
+
+	testCases := []struct {
+		name  string
+		steps []walker.Step
+		err   string
+	}{
+		{name: "duplicate", steps: []walker.Step{step("a"), step("a")}, err: `walker: duplicate step "a"`},
+		{name: "unknown", steps: []walker.Step{step("a", "b")}, err: `walker: step "a" depends on unknown step "b"`},
+		{name: "cycle", steps: []walker.Step{step("a", "c"), step("b", "a"), step("c", "b")}, err: `walker: dependency cycle through step "a"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := walker.NewOrchestrator(walker.OrchestratorConfig{}, tc.steps...).Run()
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
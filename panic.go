@@ -0,0 +1,46 @@
+package walker
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+type PanicPolicy int
+
+const (
+	PanicPolicyContinue PanicPolicy = iota
+	PanicPolicyStop
+	PanicPolicyRepanic
+)
+
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("walker: recovered panic: %v", p.Value)
+}
+
+func (w *Walker[T]) recoverPanic(start, fetchCount int) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	panicErr, ok := recovered.(*PanicError)
+	if !ok {
+		panicErr = &PanicError{Value: recovered, Stack: debug.Stack()}
+	}
+	w.storeFailedTask(start, fetchCount, panicErr)
+
+	switch w.panicPolicy {
+	case PanicPolicyStop:
+		w.Stop()
+	case PanicPolicyRepanic:
+		w.Stop()
+		w.panicOnce.Do(func() {
+			w.panicErr = panicErr
+		})
+	}
+}
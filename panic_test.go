@@ -0,0 +1,100 @@
+package walker_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+func panickingSource(panicAt int) walker.Source[[]int] {
+	return func(start, fetchCount int) ([]int, error) {
+		if start == panicAt {
+			panic("source exploded")
+		}
+		return cursorSource(100)(start, fetchCount)
+	}
+}
+
+func TestWalkerRecoversSourcePanic(t *testing.T) {
+	mockSink := MockSink{}
+	w := walker.New(
+		panickingSource(20),
+		mockSink.sink,
+		walker.WithLimiter(walker.ConstantLimiter(100)),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(1),
+	)
+
+	assert.NotPanics(t, w.Walk)
+
+	failedTasks := w.FailedTasks()
+	assert.Len(t, failedTasks, 1)
+	assert.Equal(t, 20, failedTasks[0].Start)
+
+	var panicErr *walker.PanicError
+	assert.True(t, errors.As(failedTasks[0].Err, &panicErr))
+	assert.Equal(t, "source exploded", panicErr.Value)
+	assert.NotEmpty(t, panicErr.Stack)
+	assert.Len(t, mockSink.sortedResults(), 9)
+}
+
+func TestWalkerRecoversSinkPanic(t *testing.T) {
+	sink := func(result []int, stop func()) error {
+		if result[0] == 51 {
+			panic("sink exploded")
+		}
+		return nil
+	}
+
+	w := walker.New(
+		cursorSource(100),
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(100)),
+		walker.WithPagination(walker.CursorPagination{}),
+	)
+
+	assert.NotPanics(t, w.Walk)
+	assert.Len(t, w.FailedTasks(), 1)
+	assert.Equal(t, 50, w.FailedTasks()[0].Start)
+}
+
+func TestWalkerPanicPolicyStop(t *testing.T) {
+	mockSink := MockSink{}
+	w := walker.New(
+		panickingSource(0),
+		mockSink.sink,
+		walker.WithLimiter(walker.InfiniteLimiter()),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(1),
+		walker.WithPanicPolicy(walker.PanicPolicyStop),
+	)
+
+	w.Walk()
+
+	assert.True(t, w.IsStopped())
+	assert.Len(t, w.FailedTasks(), 1)
+}
+
+func TestWalkerPanicPolicyRepanic(t *testing.T) {
+	w := walker.New(
+		panickingSource(0),
+		(&MockSink{}).sink,
+		walker.WithLimiter(walker.InfiniteLimiter()),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(1),
+		walker.WithPanicPolicy(walker.PanicPolicyRepanic),
+	)
+
+	defer func() {
+		recovered := recover()
+		panicErr, ok := recovered.(*walker.PanicError)
+		assert.True(t, ok)
+		assert.Equal(t, "source exploded", panicErr.Value)
+		assert.True(t, w.IsStopped())
+	}()
+
+	w.Walk()
+	t.Fatal("expected Walk to re-panic")
+}
@@ -0,0 +1,154 @@
+package walker
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+type Stage[In, Out any] struct {
+	process       func(ctx context.Context, in In) ([]Out, error)
+	slots         chan struct{}
+	workers       int
+	outputWorkers int
+}
+
+func newStage[In, Out any](workers int, process func(ctx context.Context, in In) ([]Out, error)) *Stage[In, Out] {
+	if workers <= 0 {
+		workers = 1
+	}
+	return &Stage[In, Out]{process: process, slots: make(chan struct{}, workers), workers: workers, outputWorkers: workers}
+}
+
+func Map[T, U any](workers int, mapper func(ctx context.Context, in T) (U, error)) *Stage[T, U] {
+	return newStage(workers, func(ctx context.Context, in T) ([]U, error) {
+		out, err := mapper(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		return []U{out}, nil
+	})
+}
+
+func Filter[T any](workers int, predicate func(ctx context.Context, in T) (bool, error)) *Stage[T, T] {
+	return newStage(workers, func(ctx context.Context, in T) ([]T, error) {
+		keep, err := predicate(ctx, in)
+		if err != nil || !keep {
+			return nil, err
+		}
+		return []T{in}, nil
+	})
+}
+
+func FlatMap[T, U any](workers int, mapper func(ctx context.Context, in T) ([]U, error)) *Stage[T, U] {
+	return newStage(workers, mapper)
+}
+
+func Then[A, B, C any](first *Stage[A, B], second *Stage[B, C]) *Stage[A, C] {
+	return &Stage[A, C]{
+		process: func(ctx context.Context, in A) ([]C, error) {
+			var mutex sync.Mutex
+			outs := make([]C, 0)
+			err := first.deliver(ctx, in, second.workers, func(ctx context.Context, out B, stop func()) error {
+				results, err := second.run(ctx, out)
+				mutex.Lock()
+				outs = append(outs, results...)
+				mutex.Unlock()
+				return err
+			}, func() {})
+			return outs, err
+		},
+		workers:       first.workers,
+		outputWorkers: second.outputWorkers,
+	}
+}
+
+func (s *Stage[In, Out]) To(sink SinkCtx[Out]) SinkCtx[In] {
+	slots := make(chan struct{}, s.outputWorkers)
+	bounded := func(ctx context.Context, out Out, stop func()) error {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		defer func() { <-slots }()
+
+		return sink(ctx, out, stop)
+	}
+
+	return func(ctx context.Context, in In, stop func()) error {
+		return s.deliver(ctx, in, s.outputWorkers, bounded, stop)
+	}
+}
+
+func (s *Stage[In, Out]) run(ctx context.Context, in In) ([]Out, error) {
+	if s.slots == nil {
+		return s.process(ctx, in)
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-s.slots }()
+
+	return s.process(ctx, in)
+}
+
+func (s *Stage[In, Out]) deliver(ctx context.Context, in In, workers int, sink SinkCtx[Out], stop func()) error {
+	outs, err := s.run(ctx, in)
+	if err != nil {
+		return err
+	}
+
+	if len(outs) == 1 {
+		return sink(ctx, outs[0], stop)
+	}
+
+	if workers > len(outs) {
+		workers = len(outs)
+	}
+
+	errs := make([]error, len(outs))
+	next := int64(-1)
+	var wg sync.WaitGroup
+	var panicErr *PanicError
+	var panicOnce sync.Once
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					panicOnce.Do(func() {
+						panicErr, _ = recovered.(*PanicError)
+						if panicErr == nil {
+							panicErr = &PanicError{Value: recovered, Stack: debug.Stack()}
+						}
+					})
+				}
+			}()
+
+			for index := int(atomic.AddInt64(&next, 1)); index < len(outs); index = int(atomic.AddInt64(&next, 1)) {
+				errs[index] = sink(ctx, outs[index], stop)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if panicErr != nil {
+		panic(panicErr)
+	}
+
+	for index, err := range errs {
+		if err == nil {
+			continue
+		}
+		if !ReportFailure(ctx, index, err) {
+			return err
+		}
+	}
+	return nil
+}
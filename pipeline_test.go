@@ -0,0 +1,148 @@
+package walker_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+func pageOfIDs(ctx context.Context, start, fetchCount int) ([]int, error) {
+	ids := make([]int, 0, fetchCount)
+	for id := start; id < start+fetchCount; id++ {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+type stringCollector struct {
+	sync.Mutex
+	items []string
+}
+
+func (c *stringCollector) sink(ctx context.Context, item string, stop func()) error {
+	c.Lock()
+	defer c.Unlock()
+	c.items = append(c.items, item)
+	return nil
+}
+
+func TestPipelineStagesTransformItems(t *testing.T) {
+	collector := &stringCollector{}
+
+	split := walker.FlatMap(1, func(ctx context.Context, page []int) ([]int, error) { return page, nil })
+	even := walker.Filter(4, func(ctx context.Context, id int) (bool, error) { return id%2 == 0, nil })
+	format := walker.Map(4, func(ctx context.Context, id int) (string, error) { return strconv.Itoa(id), nil })
+
+	w := walker.NewCtx(
+		pageOfIDs,
+		walker.Then(split, walker.Then(even, format)).To(collector.sink),
+		walker.WithLimiter(walker.ConstantLimiter(20)),
+		walker.WithPagination(walker.CursorPagination{}),
+	)
+	w.Walk()
+
+	assert.Empty(t, w.FailedTasks())
+	assert.ElementsMatch(t, []string{"0", "2", "4", "6", "8", "10", "12", "14", "16", "18"}, collector.items)
+}
+
+func TestPipelineStageRunsWithItsOwnWorkerCount(t *testing.T) {
+	var running, peak int64
+	slow := walker.Map(3, func(ctx context.Context, id int) (int, error) {
+		current := atomic.AddInt64(&running, 1)
+		defer atomic.AddInt64(&running, -1)
+		for {
+			observed := atomic.LoadInt64(&peak)
+			if current <= observed || atomic.CompareAndSwapInt64(&peak, observed, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return id, nil
+	})
+	split := walker.FlatMap(1, func(ctx context.Context, page []int) ([]int, error) { return page, nil })
+
+	w := walker.NewCtx(
+		pageOfIDs,
+		walker.Then(split, slow).To(func(ctx context.Context, id int, stop func()) error { return nil }),
+		walker.WithLimiter(walker.ConstantLimiter(30)),
+		walker.WithParallelism(1),
+		walker.WithPagination(walker.CursorPagination{}),
+	)
+	w.Walk()
+
+	assert.Equal(t, int64(3), peak)
+}
+
+func TestPipelineReportsFailedItems(t *testing.T) {
+	split := walker.FlatMap(1, func(ctx context.Context, page []int) ([]int, error) { return page, nil })
+	validate := walker.Map(2, func(ctx context.Context, id int) (int, error) {
+		if id == 3 {
+			return 0, errors.New("invalid item")
+		}
+		return id, nil
+	})
+
+	w := walker.NewCtx(
+		pageOfIDs,
+		split.To(validate.To(func(ctx context.Context, id int, stop func()) error { return nil })),
+		walker.WithLimiter(walker.ConstantLimiter(10)),
+		walker.WithMaxBatchSize(5),
+		walker.WithParallelism(1),
+		walker.WithPagination(walker.CursorPagination{}),
+	)
+	w.Walk()
+
+	failed := w.FailedTasks()
+	assert.Len(t, failed, 1)
+	assert.Equal(t, 0, failed[0].Start)
+	assert.Equal(t, 3, failed[0].ItemIndex)
+}
+
+func TestThenComposesStages(t *testing.T) {
+	collector := &stringCollector{}
+	split := walker.FlatMap(1, func(ctx context.Context, page []int) ([]int, error) { return page, nil })
+	format := walker.Map(1, func(ctx context.Context, id int) (string, error) { return "#" + strconv.Itoa(id), nil })
+
+	sink := walker.Then(split, format).To(collector.sink)
+	assert.Nil(t, sink(context.Background(), []int{1, 2, 3}, func() {}))
+
+	assert.ElementsMatch(t, []string{"#1", "#2", "#3"}, collector.items)
+}
+
+func TestPipelineBoundsTerminalSinkByLastStageWorkers(t *testing.T) {
+	var running, peak int64
+	sink := func(ctx context.Context, id int, stop func()) error {
+		current := atomic.AddInt64(&running, 1)
+		defer atomic.AddInt64(&running, -1)
+		for {
+			observed := atomic.LoadInt64(&peak)
+			if current <= observed || atomic.CompareAndSwapInt64(&peak, observed, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return nil
+	}
+	split := walker.FlatMap(1, func(ctx context.Context, page []int) ([]int, error) { return page, nil })
+	double := walker.Map(2, func(ctx context.Context, id int) (int, error) { return id * 2, nil })
+
+	w := walker.NewCtx(
+		pageOfIDs,
+		walker.Then(split, double).To(sink),
+		walker.WithLimiter(walker.ConstantLimiter(400)),
+		walker.WithMaxBatchSize(100),
+		walker.WithParallelism(4),
+		walker.WithPagination(walker.CursorPagination{}),
+	)
+	w.Walk()
+
+	assert.Empty(t, w.FailedTasks())
+	assert.Equal(t, int64(2), peak)
+}
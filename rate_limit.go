@@ -1,11 +1,238 @@
 package walker
 
-import "time"
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
 
-type rateLimit struct {
-	count     int
-	per       time.Duration
-	unlimited bool
+type RateLimiter interface {
+	Wait(ctx context.Context) error
 }
 
-var defaultRateLimiter = rateLimit{unlimited: true}
+type Refunder interface {
+	Refund()
+}
+
+type Validator interface {
+	Validate() error
+}
+
+type invalidRateLimiter struct {
+	err error
+}
+
+func (i invalidRateLimiter) Wait(ctx context.Context) error {
+	return i.err
+}
+
+func (i invalidRateLimiter) Validate() error {
+	return i.err
+}
+
+func checkRate(constructor string, count int, per time.Duration) error {
+	if count <= 0 || per <= 0 {
+		return invalidOption(constructor, "count and per must be positive, got %d per %s", count, per)
+	}
+	return nil
+}
+
+type unlimitedRateLimiter struct{}
+
+func UnlimitedRateLimiter() RateLimiter {
+	return unlimitedRateLimiter{}
+}
+
+func (unlimitedRateLimiter) Wait(ctx context.Context) error {
+	return ctx.Err()
+}
+
+func NewEvenRateLimiter(count int, per time.Duration) RateLimiter {
+	if err := checkRate("NewEvenRateLimiter", count, per); err != nil {
+		return invalidRateLimiter{err: err}
+	}
+	return NewTokenBucket(count, per, 1)
+}
+
+type tokenBucket struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+}
+
+func NewTokenBucket(count int, per time.Duration, burst int) RateLimiter {
+	if err := checkRate("NewTokenBucket", count, per); err != nil {
+		return invalidRateLimiter{err: err}
+	}
+	if burst <= 0 {
+		return invalidRateLimiter{err: invalidOption("NewTokenBucket", "burst must be positive, got %d", burst)}
+	}
+
+	interval := per / time.Duration(count)
+	if interval <= 0 {
+		interval = time.Nanosecond
+	}
+
+	return &tokenBucket{
+		interval: interval,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+float64(now.Sub(b.last))/float64(b.interval))
+	b.last = now
+	b.tokens--
+	wait := time.Duration(-b.tokens * float64(b.interval))
+	b.mutex.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, wait); err != nil {
+		b.Refund()
+		return err
+	}
+
+	return nil
+}
+
+func (b *tokenBucket) Refund() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
+
+type slidingWindow struct {
+	mutex  sync.Mutex
+	count  int
+	per    time.Duration
+	grants []time.Time
+}
+
+func NewSlidingWindow(count int, per time.Duration) RateLimiter {
+	if err := checkRate("NewSlidingWindow", count, per); err != nil {
+		return invalidRateLimiter{err: err}
+	}
+	return &slidingWindow{count: count, per: per}
+}
+
+func (s *slidingWindow) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	now := time.Now()
+	expired := 0
+	for expired < len(s.grants) && !s.grants[expired].After(now.Add(-s.per)) {
+		expired++
+	}
+	s.grants = s.grants[expired:]
+
+	grant := now
+	if len(s.grants) >= s.count {
+		if next := s.grants[len(s.grants)-s.count].Add(s.per); next.After(now) {
+			grant = next
+		}
+	}
+	s.grants = append(s.grants, grant)
+	s.mutex.Unlock()
+
+	if err := sleep(ctx, grant.Sub(now)); err != nil {
+		s.release(grant)
+		return err
+	}
+
+	return nil
+}
+
+func (s *slidingWindow) Refund() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.grants) > 0 {
+		s.grants = s.grants[:len(s.grants)-1]
+	}
+}
+
+func (s *slidingWindow) release(grant time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := len(s.grants) - 1; i >= 0; i-- {
+		if s.grants[i].Equal(grant) {
+			s.grants = append(s.grants[:i], s.grants[i+1:]...)
+			return
+		}
+	}
+}
+
+type combinedRateLimiter []RateLimiter
+
+func CombineRateLimiters(limiters ...RateLimiter) RateLimiter {
+	switch len(limiters) {
+	case 0:
+		return UnlimitedRateLimiter()
+	case 1:
+		return limiters[0]
+	}
+	return combinedRateLimiter(limiters)
+}
+
+func (c combinedRateLimiter) Wait(ctx context.Context) error {
+	for index, limiter := range c {
+		if err := limiter.Wait(ctx); err != nil {
+			c[:index].Refund()
+			return err
+		}
+	}
+	return nil
+}
+
+func (c combinedRateLimiter) Validate() error {
+	for _, limiter := range c {
+		if validator, ok := limiter.(Validator); ok {
+			if err := validator.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c combinedRateLimiter) Refund() {
+	for _, limiter := range c {
+		if refunder, ok := limiter.(Refunder); ok {
+			refunder.Refund()
+		}
+	}
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	if duration <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
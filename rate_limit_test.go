@@ -0,0 +1,142 @@
+package walker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+func waitN(t *testing.T, limiter walker.RateLimiter, n int) time.Duration {
+	began := time.Now()
+	for i := 0; i < n; i++ {
+		assert.NoError(t, limiter.Wait(context.Background()))
+	}
+	return time.Since(began)
+}
+
+func TestTokenBucketAllowsBurst(t *testing.T) {
+	limiter := walker.NewTokenBucket(20, time.Second, 5)
+
+	assert.Less(t, waitN(t, limiter, 5), 20*time.Millisecond)
+	assert.GreaterOrEqual(t, waitN(t, limiter, 1), 40*time.Millisecond)
+}
+
+func TestTokenBucketRefillsOverTime(t *testing.T) {
+	limiter := walker.NewTokenBucket(100, time.Second, 2)
+	waitN(t, limiter, 2)
+
+	time.Sleep(25 * time.Millisecond)
+
+	assert.Less(t, waitN(t, limiter, 2), 5*time.Millisecond)
+}
+
+func TestSlidingWindowLimitsCountPerWindow(t *testing.T) {
+	limiter := walker.NewSlidingWindow(3, 50*time.Millisecond)
+
+	assert.Less(t, waitN(t, limiter, 3), 10*time.Millisecond)
+	assert.GreaterOrEqual(t, waitN(t, limiter, 1), 40*time.Millisecond)
+}
+
+func TestCombinedRateLimitersRespectEveryLimit(t *testing.T) {
+	limiter := walker.CombineRateLimiters(
+		walker.NewTokenBucket(1000, time.Second, 10),
+		walker.NewSlidingWindow(2, 50*time.Millisecond),
+	)
+
+	assert.GreaterOrEqual(t, waitN(t, limiter, 3), 40*time.Millisecond)
+}
+
+func TestRateLimiterWaitIsCancellable(t *testing.T) {
+	limiters := []walker.RateLimiter{
+		walker.NewTokenBucket(1, time.Hour, 1),
+		walker.NewSlidingWindow(1, time.Hour),
+	}
+
+	for _, limiter := range limiters {
+		assert.NoError(t, limiter.Wait(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		assert.ErrorIs(t, limiter.Wait(ctx), context.DeadlineExceeded)
+		cancel()
+	}
+}
+
+func TestWalkerWithRateLimitStopsWhenContextIsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := walker.New(
+		cursorSource(50),
+		(&MockSink{}).sink,
+		walker.WithContext(ctx),
+		walker.WithLimiter(walker.ConstantLimiter(50)),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(1),
+		walker.WithRateLimit(1, 2*time.Second),
+	)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	began := time.Now()
+	w.Walk()
+
+	assert.Less(t, time.Since(began), 500*time.Millisecond)
+}
+
+func TestWalkerWithRateLimiter(t *testing.T) {
+	mockSink := MockSink{}
+	w := walker.New(
+		cursorSource(50),
+		mockSink.sink,
+		walker.WithLimiter(walker.ConstantLimiter(50)),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(1),
+		walker.WithRateLimiter(walker.NewSlidingWindow(2, 30*time.Millisecond)),
+	)
+
+	began := time.Now()
+	w.Walk()
+
+	assert.GreaterOrEqual(t, time.Since(began), 60*time.Millisecond)
+	assert.Equal(t, makeExpectedOutput(50, 10), mockSink.sortedResults())
+}
+
+type failingRateLimiter struct{}
+
+func (failingRateLimiter) Wait(ctx context.Context) error {
+	return context.Canceled
+}
+
+func TestCombinedRateLimiterRefundsEarlierLimitersOnFailure(t *testing.T) {
+	bucket := walker.NewTokenBucket(1, time.Hour, 1)
+	window := walker.NewSlidingWindow(1, time.Hour)
+	combined := walker.CombineRateLimiters(bucket, window, failingRateLimiter{})
+
+	assert.ErrorIs(t, combined.Wait(context.Background()), context.Canceled)
+
+	assert.Less(t, waitN(t, bucket, 1), 10*time.Millisecond)
+	assert.Less(t, waitN(t, window, 1), 10*time.Millisecond)
+}
+
+func TestRateLimiterConstructorsRejectInvalidRates(t *testing.T) {
+	limiters := []walker.RateLimiter{
+		walker.NewEvenRateLimiter(0, time.Millisecond),
+		walker.NewTokenBucket(-1, time.Millisecond, 1),
+		walker.NewTokenBucket(1, -time.Second, 1),
+		walker.NewTokenBucket(1, time.Second, 0),
+		walker.NewSlidingWindow(0, time.Millisecond),
+	}
+
+	for _, limiter := range limiters {
+		assert.ErrorIs(t, limiter.Wait(context.Background()), walker.ErrInvalidOption)
+		assert.ErrorIs(t, walker.Validate(walker.WithRateLimiter(limiter)), walker.ErrInvalidOption)
+		assert.ErrorIs(t, walker.Validate(walker.WithSharedRateLimiter(walker.NewSharedRateLimiter("quota", limiter))), walker.ErrInvalidOption)
+		assert.Panics(t, func() {
+			walker.New(cursorSource(10), (&MockSink{}).sink, walker.WithRateLimiter(walker.CombineRateLimiters(walker.UnlimitedRateLimiter(), limiter)))
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package redislimiter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"github.com/redis/go-redis/v9"
+)
+
+var gcra = redis.NewScript(`
+local now = redis.call('TIME')
+now = tonumber(now[1]) * 1000000 + tonumber(now[2])
+
+local interval = tonumber(ARGV[1])
+local tolerance = tonumber(ARGV[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if not tat or tat < now then
+	tat = now
+end
+
+local wait = tat - tolerance - now
+if wait < 0 then
+	wait = 0
+end
+
+local next_tat = tat + interval
+redis.call('SET', KEYS[1], string.format('%d', next_tat), 'PX', math.ceil((next_tat - now) / 1000) + 1)
+
+return wait
+`)
+
+var refund = redis.NewScript(`
+local now = redis.call('TIME')
+now = tonumber(now[1]) * 1000000 + tonumber(now[2])
+
+local interval = tonumber(ARGV[1])
+
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if not tat or tat <= now then
+	return 0
+end
+
+local next_tat = math.max(now, tat - interval)
+redis.call('SET', KEYS[1], string.format('%d', next_tat), 'PX', math.ceil((next_tat - now) / 1000) + 1)
+
+return 1
+`)
+
+const refundTimeout = time.Second
+
+type Option func(*RateLimiter)
+
+type RateLimiter struct {
+	client   redis.Scripter
+	key      string
+	interval time.Duration
+	burst    int
+	err      error
+}
+
+func WithBurst(burst int) Option {
+	return func(r *RateLimiter) {
+		r.burst = burst
+	}
+}
+
+func New(client redis.Scripter, key string, count int, per time.Duration, options ...Option) *RateLimiter {
+	limiter := &RateLimiter{
+		client: client,
+		key:    key,
+		burst:  1,
+	}
+
+	for _, option := range options {
+		option(limiter)
+	}
+
+	switch {
+	case count <= 0 || per <= 0:
+		limiter.err = fmt.Errorf("%w: redislimiter.New count and per must be positive, got %d per %s", walker.ErrInvalidOption, count, per)
+	case limiter.burst <= 0:
+		limiter.err = fmt.Errorf("%w: redislimiter.WithBurst must be positive, got %d", walker.ErrInvalidOption, limiter.burst)
+	default:
+		limiter.interval = per / time.Duration(count)
+	}
+
+	return limiter
+}
+
+func (r *RateLimiter) Validate() error {
+	return r.err
+}
+
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	interval := r.interval.Microseconds()
+	tolerance := interval * int64(r.burst-1)
+
+	wait, err := gcra.Run(ctx, r.client, []string{r.key}, interval, tolerance).Int64()
+	if err != nil {
+		return err
+	}
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(time.Duration(wait) * time.Microsecond)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		r.Refund()
+		return ctx.Err()
+	}
+}
+
+func (r *RateLimiter) Refund() {
+	ctx, cancel := context.WithTimeout(context.Background(), refundTimeout)
+	defer cancel()
+	refund.Run(ctx, r.client, []string{r.key}, r.interval.Microseconds())
+}
+
+var (
+	_ walker.RateLimiter = (*RateLimiter)(nil)
+	_ walker.Refunder    = (*RateLimiter)(nil)
+	_ walker.Validator   = (*RateLimiter)(nil)
+)
@@ -0,0 +1,119 @@
+package redislimiter_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/cyucelen/walker"
+	"github.com/cyucelen/walker/redislimiter"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newClient(t *testing.T) *redis.Client {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestRateLimiterAllowsBurstThenSpacesCalls(t *testing.T) {
+	limiter := redislimiter.New(newClient(t), "quota", 20, time.Second, redislimiter.WithBurst(3))
+
+	began := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, limiter.Wait(context.Background()))
+	}
+	assert.Less(t, time.Since(began), 40*time.Millisecond)
+
+	began = time.Now()
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(began), 30*time.Millisecond)
+}
+
+func TestRateLimiterIsSharedBetweenReplicas(t *testing.T) {
+	client := newClient(t)
+	replicas := []*redislimiter.RateLimiter{
+		redislimiter.New(client, "quota", 10, 100*time.Millisecond),
+		redislimiter.New(client, "quota", 10, 100*time.Millisecond),
+	}
+
+	began := time.Now()
+	var wg sync.WaitGroup
+	for _, replica := range replicas {
+		wg.Add(1)
+		go func(replica *redislimiter.RateLimiter) {
+			defer wg.Done()
+			for i := 0; i < 5; i++ {
+				assert.NoError(t, replica.Wait(context.Background()))
+			}
+		}(replica)
+	}
+	wg.Wait()
+
+	assert.GreaterOrEqual(t, time.Since(began), 80*time.Millisecond)
+}
+
+func TestRateLimiterWaitIsCancellable(t *testing.T) {
+	limiter := redislimiter.New(newClient(t), "quota", 1, time.Hour)
+	assert.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, limiter.Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestWalkerWithRedisRateLimiter(t *testing.T) {
+	var mutex sync.Mutex
+	starts := make([]int, 0)
+	source := func(start, fetchCount int) (int, error) {
+		return start, nil
+	}
+	sink := func(start int, stop func()) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		starts = append(starts, start)
+		return nil
+	}
+
+	w := walker.New(
+		source,
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(50)),
+		walker.WithRateLimiter(redislimiter.New(newClient(t), "walker", 100, time.Second)),
+	)
+
+	began := time.Now()
+	w.Walk()
+
+	assert.GreaterOrEqual(t, time.Since(began), 30*time.Millisecond)
+	assert.Len(t, starts, 5)
+}
+
+func TestRateLimiterRefundsCancelledReservation(t *testing.T) {
+	limiter := redislimiter.New(newClient(t), "quota", 10, time.Second)
+	assert.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, limiter.Wait(ctx), context.DeadlineExceeded)
+
+	began := time.Now()
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.Less(t, time.Since(began), 180*time.Millisecond)
+}
+
+func TestNewRejectsInvalidRate(t *testing.T) {
+	for _, limiter := range []*redislimiter.RateLimiter{
+		redislimiter.New(newClient(t), "quota", 0, time.Second),
+		redislimiter.New(newClient(t), "quota", 10, time.Second, redislimiter.WithBurst(0)),
+	} {
+		assert.ErrorIs(t, limiter.Validate(), walker.ErrInvalidOption)
+		assert.ErrorIs(t, limiter.Wait(context.Background()), walker.ErrInvalidOption)
+		assert.ErrorIs(t, walker.Validate(walker.WithRateLimiter(limiter)), walker.ErrInvalidOption)
+	}
+}
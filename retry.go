@@ -0,0 +1,54 @@
+package walker
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+type retries struct {
+	attempts int
+	backoff  time.Duration
+}
+
+func (r retries) delay(attempt int) time.Duration {
+	if attempt >= 63 || r.backoff > math.MaxInt64>>attempt {
+		return math.MaxInt64
+	}
+	return r.backoff << attempt
+}
+
+func (w *Walker[T]) fetch(ctx context.Context, start, fetchCount int) (T, error) {
+	result, err := w.fetchOnce(ctx, start, fetchCount)
+	for attempt := 0; w.shouldRetry(result, err) && attempt < w.retries.attempts; attempt++ {
+		if sleep(ctx, w.retryDelay(attempt, result, err)) != nil || !w.budget.takeCall() {
+			break
+		}
+		if w.rateLimiter.Wait(ctx) != nil {
+			w.budget.refundCall()
+			break
+		}
+		if err == nil && w.discard != nil {
+			w.discard(result)
+		}
+		result, err = w.fetchOnce(ctx, start, fetchCount)
+	}
+	return result, err
+}
+
+func (w *Walker[T]) retryDelay(attempt int, result T, err error) time.Duration {
+	delay := w.retries.delay(attempt)
+	if err == nil && w.retryAfter != nil {
+		if after := w.retryAfter(result); after > delay {
+			delay = after
+		}
+	}
+	return delay
+}
+
+func (w *Walker[T]) shouldRetry(result T, err error) bool {
+	if err != nil {
+		return true
+	}
+	return w.retryResult != nil && w.retryResult(result)
+}
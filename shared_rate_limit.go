@@ -0,0 +1,146 @@
+package walker
+
+import (
+	"context"
+	"sync"
+)
+
+type SharedRateLimiter struct {
+	name    string
+	limiter RateLimiter
+	mutex   sync.Mutex
+	clients []*sharedRateLimiterClient
+	cursor  int
+	busy    bool
+}
+
+type sharedRateLimiterClient struct {
+	shared  *SharedRateLimiter
+	waiters []chan struct{}
+}
+
+func NewSharedRateLimiter(name string, limiter RateLimiter) *SharedRateLimiter {
+	return &SharedRateLimiter{name: name, limiter: limiter}
+}
+
+func (s *SharedRateLimiter) Name() string {
+	return s.name
+}
+
+func (s *SharedRateLimiter) Client() RateLimiter {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	client := &sharedRateLimiterClient{shared: s}
+	s.clients = append(s.clients, client)
+	return client
+}
+
+func (s *SharedRateLimiter) Release(client RateLimiter) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for index, registered := range s.clients {
+		if registered != client {
+			continue
+		}
+
+		s.clients = append(s.clients[:index], s.clients[index+1:]...)
+		if index < s.cursor {
+			s.cursor--
+		}
+		return
+	}
+}
+
+func (s *SharedRateLimiter) Clients() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.clients)
+}
+
+type sharedClient struct {
+	shared *SharedRateLimiter
+	client RateLimiter
+}
+
+func (w *Walker[T]) releaseSharedClients() {
+	for _, shared := range w.sharedClients {
+		shared.shared.Release(shared.client)
+	}
+	w.sharedClients = nil
+}
+
+func (c *sharedRateLimiterClient) Wait(ctx context.Context) error {
+	return c.shared.wait(ctx, c)
+}
+
+func (c *sharedRateLimiterClient) Refund() {
+	if refunder, ok := c.shared.limiter.(Refunder); ok {
+		refunder.Refund()
+	}
+}
+
+func (s *SharedRateLimiter) wait(ctx context.Context, client *sharedRateLimiterClient) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	turn := make(chan struct{})
+	s.mutex.Lock()
+	client.waiters = append(client.waiters, turn)
+	if !s.busy {
+		s.busy = true
+		s.grantNext()
+	}
+	s.mutex.Unlock()
+
+	select {
+	case <-turn:
+	case <-ctx.Done():
+		s.mutex.Lock()
+		if client.remove(turn) {
+			s.mutex.Unlock()
+			return ctx.Err()
+		}
+		s.grantNext()
+		s.mutex.Unlock()
+		return ctx.Err()
+	}
+
+	err := s.limiter.Wait(ctx)
+
+	s.mutex.Lock()
+	s.grantNext()
+	s.mutex.Unlock()
+
+	return err
+}
+
+func (s *SharedRateLimiter) grantNext() {
+	for i := 0; i < len(s.clients); i++ {
+		index := (s.cursor + i) % len(s.clients)
+		client := s.clients[index]
+		if len(client.waiters) == 0 {
+			continue
+		}
+
+		turn := client.waiters[0]
+		client.waiters = client.waiters[1:]
+		s.cursor = index + 1
+		close(turn)
+		return
+	}
+
+	s.busy = false
+}
+
+func (c *sharedRateLimiterClient) remove(turn chan struct{}) bool {
+	for i, waiter := range c.waiters {
+		if waiter == turn {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package walker_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharedRateLimiterSchedulesClientsFairly(t *testing.T) {
+	shared := walker.NewSharedRateLimiter("upstream", walker.NewSlidingWindow(1, 2*time.Millisecond))
+	busy, quiet := shared.Client(), shared.Client()
+
+	var mutex sync.Mutex
+	grants := make([]string, 0)
+	record := func(name string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		grants = append(grants, name)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for busy.Wait(ctx) == nil {
+				record("busy")
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for quiet.Wait(ctx) == nil {
+			record("quiet")
+		}
+	}()
+
+	assert.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(grants) >= 20
+	}, 5*time.Second, time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	quietGrants := 0
+	for _, name := range grants[:20] {
+		if name == "quiet" {
+			quietGrants++
+		}
+	}
+	assert.GreaterOrEqual(t, quietGrants, 8)
+	assert.Equal(t, "upstream", shared.Name())
+}
+
+func TestWalkersShareRateLimiter(t *testing.T) {
+	shared := walker.NewSharedRateLimiter("upstream", walker.NewSlidingWindow(2, 30*time.Millisecond))
+	newWalker := func(sink *MockSink) *walker.Walker[[]int] {
+		return walker.New(
+			cursorSource(20),
+			sink.sink,
+			walker.WithLimiter(walker.ConstantLimiter(20)),
+			walker.WithPagination(walker.CursorPagination{}),
+			walker.WithParallelism(1),
+			walker.WithSharedRateLimiter(shared),
+		)
+	}
+
+	firstSink, secondSink := MockSink{}, MockSink{}
+	first, second := newWalker(&firstSink), newWalker(&secondSink)
+
+	began := time.Now()
+	var wg sync.WaitGroup
+	for _, w := range []*walker.Walker[[]int]{first, second} {
+		wg.Add(1)
+		go func(w *walker.Walker[[]int]) {
+			defer wg.Done()
+			w.Walk()
+		}(w)
+	}
+	wg.Wait()
+
+	assert.GreaterOrEqual(t, time.Since(began), 30*time.Millisecond)
+	assert.Equal(t, makeExpectedOutput(20, 10), firstSink.sortedResults())
+	assert.Equal(t, makeExpectedOutput(20, 10), secondSink.sortedResults())
+}
+
+func TestSharedRateLimiterClientsAreReleasedAfterWalk(t *testing.T) {
+	shared := walker.NewSharedRateLimiter("upstream", walker.UnlimitedRateLimiter())
+
+	assert.Nil(t, walker.Validate(walker.WithSharedRateLimiter(shared)))
+	_, err := walker.NewE[[]int](nil, nil, walker.WithSharedRateLimiter(shared))
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, shared.Clients())
+
+	w := walker.New(cursorSource(20), (&MockSink{}).sink, walker.WithLimiter(walker.ConstantLimiter(20)), walker.WithSharedRateLimiter(shared))
+	assert.Equal(t, 1, shared.Clients())
+
+	w.Walk()
+	assert.Equal(t, 0, shared.Clients())
+}
@@ -0,0 +1,112 @@
+package sinks
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+)
+
+type CSV[T any] struct {
+	mutex  sync.Mutex
+	fields []structField
+	file   *rotatingFile
+}
+
+func NewCSV[T any](path string, options ...FileOption) (*CSV[T], error) {
+	fields, err := structFields(reflect.TypeOf((*T)(nil)).Elem(), "csv", fieldName)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &CSV[T]{fields: fields}
+	sink.file = newRotatingFile(path, sink.writeHeader, options...)
+	return sink, nil
+}
+
+func (c *CSV[T]) Header() []string {
+	header := make([]string, 0, len(c.fields))
+	for _, field := range c.fields {
+		header = append(header, field.name)
+	}
+	return header
+}
+
+func (c *CSV[T]) writeHeader(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(c.Header()); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (c *CSV[T]) Write(items ...T) error {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	record := make([]string, len(c.fields))
+	for index, item := range items {
+		value, ok := structValue(item)
+		if !ok {
+			return fmt.Errorf("%w: index %d", ErrNilItem, index)
+		}
+
+		for i, field := range c.fields {
+			record[i] = ""
+			if column, ok := fieldValue(value, field); ok {
+				record[i] = formatCSVValue(column)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.file.write(buffer.Bytes())
+}
+
+func formatCSVValue(value reflect.Value) string {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+
+	switch v := value.Interface().(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+func (c *CSV[T]) Sink(items []T, stop func()) error {
+	return c.Write(items...)
+}
+
+func (c *CSV[T]) Item(item T, stop func()) error {
+	return c.Write(item)
+}
+
+func (c *CSV[T]) Files() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]string{}, c.file.paths...)
+}
+
+func (c *CSV[T]) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.file.close()
+}
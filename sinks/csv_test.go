@@ -0,0 +1,117 @@
+package sinks_test
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker/sinks"
+	"github.com/stretchr/testify/assert"
+)
+
+type row struct {
+	ID       int
+	Name     string `csv:"full_name"`
+	Secret   string `csv:"-"`
+	Score    *float64
+	Created  time.Time
+	internal string
+}
+
+func readCSV(t *testing.T, path string) [][]string {
+	file, err := os.Open(path)
+	assert.Nil(t, err)
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	assert.Nil(t, err)
+	return records
+}
+
+func TestCSVInfersHeaderFromStructFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rows.csv")
+	sink, err := sinks.NewCSV[*row](path)
+	assert.Nil(t, err)
+
+	score := 1.5
+	created := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	err = sink.Sink([]*row{
+		{ID: 1, Name: "first, row", Secret: "hidden", Score: &score, Created: created},
+		{ID: 2, Name: "second"},
+	}, func() {})
+	assert.Nil(t, err)
+	assert.Nil(t, sink.Close())
+
+	assert.Equal(t, [][]string{
+		{"ID", "full_name", "Score", "Created"},
+		{"1", "first, row", "1.5", "2023-01-02T03:04:05Z"},
+		{"2", "second", "", "0001-01-01T00:00:00Z"},
+	}, readCSV(t, path))
+}
+
+type Audit struct {
+	ID int
+}
+
+type auditedRow struct {
+	*Audit
+	Name string
+}
+
+func TestCSVWritesEmptyValuesForNilEmbeddedStructs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rows.csv")
+	sink, err := sinks.NewCSV[auditedRow](path)
+	assert.Nil(t, err)
+
+	assert.Nil(t, sink.Write(auditedRow{Name: "a"}, auditedRow{Audit: &Audit{ID: 2}, Name: "b"}))
+	assert.Nil(t, sink.Close())
+
+	assert.Equal(t, [][]string{{"ID", "Name"}, {"", "a"}, {"2", "b"}}, readCSV(t, path))
+}
+
+func TestCSVWritesHeaderToEveryRotatedFile(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := sinks.NewCSV[record](filepath.Join(dir, "records.csv"), sinks.WithMaxAge(20*time.Millisecond))
+	assert.Nil(t, err)
+
+	assert.Nil(t, sink.Item(record{ID: 1, Name: "first"}, func() {}))
+	time.Sleep(30 * time.Millisecond)
+	assert.Nil(t, sink.Item(record{ID: 2, Name: "second"}, func() {}))
+	assert.Nil(t, sink.Close())
+
+	files := sink.Files()
+	assert.Equal(t, []string{filepath.Join(dir, "records-000001.csv"), filepath.Join(dir, "records-000002.csv")}, files)
+	assert.Equal(t, [][]string{{"id", "name"}, {"1", "first"}}, readCSV(t, files[0]))
+	assert.Equal(t, [][]string{{"id", "name"}, {"2", "second"}}, readCSV(t, files[1]))
+}
+
+func TestCSVRejectsNonStructItems(t *testing.T) {
+	_, err := sinks.NewCSV[int](filepath.Join(t.TempDir(), "ints.csv"))
+	assert.ErrorIs(t, err, sinks.ErrNotStruct)
+}
+
+func TestCSVRejectsNilItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rows.csv")
+	sink, err := sinks.NewCSV[*row](path)
+	assert.Nil(t, err)
+
+	err = sink.Write(&row{ID: 1}, nil)
+	assert.ErrorIs(t, err, sinks.ErrNilItem)
+	assert.EqualError(t, err, "sinks: item must not be nil: index 1")
+	assert.Nil(t, sink.Close())
+	assert.Empty(t, sink.Files())
+}
+
+func TestCSVWriteAfterCloseKeepsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.csv")
+	sink, err := sinks.NewCSV[record](path)
+	assert.Nil(t, err)
+
+	assert.Nil(t, sink.Item(record{ID: 1, Name: "first"}, func() {}))
+	assert.Nil(t, sink.Close())
+	assert.ErrorIs(t, sink.Item(record{ID: 2, Name: "second"}, func() {}), sinks.ErrClosed)
+
+	assert.Equal(t, [][]string{{"id", "name"}, {"1", "first"}}, readCSV(t, path))
+}
@@ -0,0 +1,83 @@
+package sinks
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrNotStruct = errors.New("sinks: items must be structs or pointers to structs")
+	ErrNilItem   = errors.New("sinks: item must not be nil")
+)
+
+type structField struct {
+	name  string
+	index []int
+}
+
+func structFields(typ reflect.Type, tag string, name func(string) string) ([]structField, error) {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+
+	fields := make([]structField, 0, typ.NumField())
+	for _, field := range reflect.VisibleFields(typ) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		fieldName := name(field.Name)
+		if value, ok := field.Tag.Lookup(tag); ok {
+			if value == "-" {
+				continue
+			}
+			if value != "" {
+				fieldName = value
+			}
+		}
+		fields = append(fields, structField{name: fieldName, index: field.Index})
+	}
+
+	return fields, nil
+}
+
+func fieldValue(item reflect.Value, field structField) (reflect.Value, bool) {
+	value, err := item.FieldByIndexErr(field.index)
+	return value, err == nil
+}
+
+func structValue(item any) (reflect.Value, bool) {
+	value := reflect.ValueOf(item)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return value, false
+		}
+		value = value.Elem()
+	}
+	return value, value.Kind() == reflect.Struct
+}
+
+func fieldName(name string) string {
+	return name
+}
+
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			previousLower := i > 0 && unicode.IsLower(runes[i-1])
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])
+			if previousLower || nextLower {
+				builder.WriteByte('_')
+			}
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+	return builder.String()
+}
@@ -0,0 +1,176 @@
+package sinks
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var ErrClosed = errors.New("sinks: write to closed file sink")
+
+type FileOption func(*fileConfig)
+
+type fileConfig struct {
+	gzip     bool
+	maxBytes int64
+	maxAge   time.Duration
+}
+
+func WithGzip() FileOption {
+	return func(c *fileConfig) {
+		c.gzip = true
+	}
+}
+
+func WithMaxBytes(maxBytes int64) FileOption {
+	return func(c *fileConfig) {
+		c.maxBytes = maxBytes
+	}
+}
+
+func WithMaxAge(maxAge time.Duration) FileOption {
+	return func(c *fileConfig) {
+		c.maxAge = maxAge
+	}
+}
+
+type rotatingFile struct {
+	fileConfig
+	path    string
+	onOpen  func(w io.Writer) error
+	file    *os.File
+	gzip    *gzip.Writer
+	writer  io.Writer
+	written int64
+	opened  time.Time
+	index   int
+	paths   []string
+	closed  bool
+}
+
+func newRotatingFile(path string, onOpen func(w io.Writer) error, options ...FileOption) *rotatingFile {
+	file := &rotatingFile{path: path, onOpen: onOpen}
+	for _, option := range options {
+		option(&file.fileConfig)
+	}
+	return file
+}
+
+func (r *rotatingFile) rotates() bool {
+	return r.maxBytes > 0 || r.maxAge > 0
+}
+
+func (r *rotatingFile) nextPath() string {
+	path := r.path
+	if r.fileConfig.gzip && !strings.HasSuffix(path, ".gz") {
+		path += ".gz"
+	}
+
+	if !r.rotates() {
+		return path
+	}
+
+	dir, base := filepath.Split(path)
+	name, ext := base, ""
+	if dot := strings.Index(base, "."); dot > 0 {
+		name, ext = base[:dot], base[dot:]
+	}
+	return filepath.Join(dir, fmt.Sprintf("%s-%06d%s", name, r.index, ext))
+}
+
+func (r *rotatingFile) write(data []byte) error {
+	if r.closed {
+		return ErrClosed
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	if r.file != nil && r.shouldRotate(int64(len(data))) {
+		if err := r.closeFile(); err != nil {
+			return err
+		}
+	}
+
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return err
+		}
+	}
+
+	n, err := r.writer.Write(data)
+	r.written += int64(n)
+	return err
+}
+
+func (r *rotatingFile) shouldRotate(size int64) bool {
+	if r.maxBytes > 0 && r.written > 0 && r.written+size > r.maxBytes {
+		return true
+	}
+	return r.maxAge > 0 && time.Since(r.opened) >= r.maxAge
+}
+
+func (r *rotatingFile) open() error {
+	r.index++
+	path := r.nextPath()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	r.file, r.writer, r.written, r.opened = file, file, 0, time.Now()
+	r.paths = append(r.paths, path)
+	if r.fileConfig.gzip {
+		r.gzip = gzip.NewWriter(file)
+		r.writer = r.gzip
+	}
+
+	if r.onOpen == nil {
+		return nil
+	}
+	return r.onOpen(&countingWriter{writer: r.writer, written: &r.written})
+}
+
+func (r *rotatingFile) close() error {
+	r.closed = true
+	return r.closeFile()
+}
+
+func (r *rotatingFile) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+
+	var err error
+	if r.gzip != nil {
+		err = r.gzip.Close()
+		r.gzip = nil
+	}
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	r.file, r.writer = nil, nil
+
+	return err
+}
+
+type countingWriter struct {
+	writer  io.Writer
+	written *int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.writer.Write(p)
+	*c.written += int64(n)
+	return n, err
+}
@@ -0,0 +1,50 @@
+package sinks
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+type JSONLines[T any] struct {
+	mutex sync.Mutex
+	file  *rotatingFile
+}
+
+func NewJSONLines[T any](path string, options ...FileOption) *JSONLines[T] {
+	return &JSONLines[T]{file: newRotatingFile(path, nil, options...)}
+}
+
+func (j *JSONLines[T]) Write(items ...T) error {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	for _, item := range items {
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.file.write(buffer.Bytes())
+}
+
+func (j *JSONLines[T]) Sink(items []T, stop func()) error {
+	return j.Write(items...)
+}
+
+func (j *JSONLines[T]) Item(item T, stop func()) error {
+	return j.Write(item)
+}
+
+func (j *JSONLines[T]) Files() []string {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return append([]string{}, j.file.paths...)
+}
+
+func (j *JSONLines[T]) Close() error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.file.close()
+}
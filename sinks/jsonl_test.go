@@ -0,0 +1,109 @@
+package sinks_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/cyucelen/walker"
+	"github.com/cyucelen/walker/sinks"
+	"github.com/stretchr/testify/assert"
+)
+
+type record struct {
+	ID   int    `json:"id" csv:"id"`
+	Name string `json:"name" csv:"name"`
+}
+
+func recordSource(start, fetchCount int) ([]record, error) {
+	records := make([]record, 0, fetchCount)
+	for id := start; id < start+fetchCount; id++ {
+		records = append(records, record{ID: id, Name: "record"})
+	}
+	return records, nil
+}
+
+func readLines(t *testing.T, path string, gzipped bool) []string {
+	file, err := os.Open(path)
+	assert.Nil(t, err)
+	defer file.Close()
+
+	var scanner *bufio.Scanner
+	if gzipped {
+		reader, err := gzip.NewReader(file)
+		assert.Nil(t, err)
+		scanner = bufio.NewScanner(reader)
+	} else {
+		scanner = bufio.NewScanner(file)
+	}
+
+	lines := []string{}
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+func TestJSONLinesWritesAllItemsOfParallelWalk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.jsonl")
+	sink := sinks.NewJSONLines[record](path)
+
+	walker.New[[]record](
+		recordSource,
+		sink.Sink,
+		walker.WithLimiter(walker.ConstantLimiter(200)),
+		walker.WithParallelism(4),
+		walker.WithPagination(walker.CursorPagination{}),
+	).Walk()
+	assert.Nil(t, sink.Close())
+
+	ids := []int{}
+	for _, line := range readLines(t, path, false) {
+		var r record
+		assert.Nil(t, json.Unmarshal([]byte(line), &r))
+		ids = append(ids, r.ID)
+	}
+	sort.Ints(ids)
+
+	expected := make([]int, 200)
+	for i := range expected {
+		expected[i] = i
+	}
+	assert.Equal(t, expected, ids)
+	assert.Equal(t, []string{path}, sink.Files())
+}
+
+func TestJSONLinesRotatesGzipFilesBySize(t *testing.T) {
+	dir := t.TempDir()
+	sink := sinks.NewJSONLines[record](filepath.Join(dir, "records.jsonl"), sinks.WithGzip(), sinks.WithMaxBytes(100))
+
+	for id := 0; id < 10; id++ {
+		assert.Nil(t, sink.Item(record{ID: id, Name: "record"}, func() {}))
+	}
+	assert.Nil(t, sink.Close())
+
+	files := sink.Files()
+	assert.Equal(t, filepath.Join(dir, "records-000001.jsonl.gz"), files[0])
+	assert.Greater(t, len(files), 1)
+
+	count := 0
+	for _, file := range files {
+		count += len(readLines(t, file, true))
+	}
+	assert.Equal(t, 10, count)
+}
+
+func TestJSONLinesWriteAfterCloseKeepsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.jsonl")
+	sink := sinks.NewJSONLines[record](path)
+
+	assert.Nil(t, sink.Write(record{ID: 1, Name: "first"}))
+	assert.Nil(t, sink.Close())
+	assert.ErrorIs(t, sink.Write(record{ID: 2, Name: "second"}), sinks.ErrClosed)
+
+	assert.Equal(t, []string{`{"id":1,"name":"first"}`}, readLines(t, path, false))
+}
@@ -0,0 +1,226 @@
+package sinks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var ErrMissingTable = errors.New("sinks: sql table is required")
+
+type Dialect interface {
+	Placeholder(index int) string
+	Quote(identifier string) string
+	Upsert(conflictColumns, updateColumns []string) string
+}
+
+var (
+	Postgres Dialect = postgresDialect{}
+	SQLite   Dialect = sqliteDialect{}
+	MySQL    Dialect = mysqlDialect{}
+)
+
+type postgresDialect struct{}
+
+func (postgresDialect) Placeholder(index int) string {
+	return fmt.Sprintf("$%d", index)
+}
+
+func (postgresDialect) Quote(identifier string) string {
+	return quoteWith(identifier, `"`)
+}
+
+func (postgresDialect) Upsert(conflictColumns, updateColumns []string) string {
+	return onConflict(conflictColumns, updateColumns)
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Placeholder(index int) string {
+	return "?"
+}
+
+func (sqliteDialect) Quote(identifier string) string {
+	return quoteWith(identifier, `"`)
+}
+
+func (sqliteDialect) Upsert(conflictColumns, updateColumns []string) string {
+	return onConflict(conflictColumns, updateColumns)
+}
+
+func onConflict(conflictColumns, updateColumns []string) string {
+	clause := fmt.Sprintf(" ON CONFLICT (%s)", strings.Join(conflictColumns, ", "))
+	if len(updateColumns) == 0 {
+		return clause + " DO NOTHING"
+	}
+
+	sets := make([]string, 0, len(updateColumns))
+	for _, column := range updateColumns {
+		sets = append(sets, fmt.Sprintf("%s = excluded.%s", column, column))
+	}
+	return clause + " DO UPDATE SET " + strings.Join(sets, ", ")
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Placeholder(index int) string {
+	return "?"
+}
+
+func (mysqlDialect) Quote(identifier string) string {
+	return quoteWith(identifier, "`")
+}
+
+func (mysqlDialect) Upsert(conflictColumns, updateColumns []string) string {
+	if len(updateColumns) == 0 {
+		updateColumns = conflictColumns[:1]
+	}
+
+	sets := make([]string, 0, len(updateColumns))
+	for _, column := range updateColumns {
+		sets = append(sets, fmt.Sprintf("%s = VALUES(%s)", column, column))
+	}
+	return " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+}
+
+func quoteWith(identifier, quote string) string {
+	return quote + strings.ReplaceAll(identifier, quote, quote+quote) + quote
+}
+
+func quoteName(dialect Dialect, name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = dialect.Quote(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func quoteNames(dialect Dialect, names []string) []string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, dialect.Quote(name))
+	}
+	return quoted
+}
+
+type SQLConfig struct {
+	Table           string
+	Dialect         Dialect
+	ConflictColumns []string
+	RowsPerInsert   int
+}
+
+type SQL[T any] struct {
+	db      *sql.DB
+	config  SQLConfig
+	fields  []structField
+	columns []string
+	table   string
+	quoted  string
+	upsert  string
+}
+
+func NewSQL[T any](db *sql.DB, config SQLConfig) (*SQL[T], error) {
+	if config.Table == "" {
+		return nil, ErrMissingTable
+	}
+	if config.Dialect == nil {
+		config.Dialect = Postgres
+	}
+	if config.RowsPerInsert <= 0 {
+		config.RowsPerInsert = 100
+	}
+
+	fields, err := structFields(reflect.TypeOf((*T)(nil)).Elem(), "db", snakeCase)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &SQL[T]{db: db, config: config, fields: fields, table: quoteName(config.Dialect, config.Table)}
+	conflicts := make(map[string]bool, len(config.ConflictColumns))
+	for _, column := range config.ConflictColumns {
+		conflicts[column] = true
+	}
+
+	updates := make([]string, 0, len(fields))
+	for _, field := range fields {
+		sink.columns = append(sink.columns, field.name)
+		if !conflicts[field.name] {
+			updates = append(updates, field.name)
+		}
+	}
+	sink.quoted = strings.Join(quoteNames(config.Dialect, sink.columns), ", ")
+	if len(config.ConflictColumns) > 0 {
+		sink.upsert = config.Dialect.Upsert(quoteNames(config.Dialect, config.ConflictColumns), quoteNames(config.Dialect, updates))
+	}
+
+	return sink, nil
+}
+
+func (s *SQL[T]) Columns() []string {
+	return append([]string{}, s.columns...)
+}
+
+func (s *SQL[T]) Write(ctx context.Context, items []T) (err error) {
+	rows := make([]reflect.Value, 0, len(items))
+	for index, item := range items {
+		value, ok := structValue(item)
+		if !ok {
+			return fmt.Errorf("%w: index %d", ErrNilItem, index)
+		}
+		rows = append(rows, value)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	for start := 0; start < len(rows); start += s.config.RowsPerInsert {
+		end := start + s.config.RowsPerInsert
+		if end > len(rows) {
+			end = len(rows)
+		}
+		query, args := s.insert(rows[start:end])
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQL[T]) insert(rows []reflect.Value) (string, []any) {
+	args := make([]any, 0, len(rows)*len(s.fields))
+	values := make([]string, 0, len(rows))
+	placeholders := make([]string, len(s.fields))
+	for _, row := range rows {
+		for i, field := range s.fields {
+			var arg any
+			if value, ok := fieldValue(row, field); ok {
+				arg = value.Interface()
+			}
+			args = append(args, arg)
+			placeholders[i] = s.config.Dialect.Placeholder(len(args))
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s%s", s.table, s.quoted, strings.Join(values, ", "), s.upsert)
+	return query, args
+}
+
+func (s *SQL[T]) Sink(ctx context.Context, items []T, stop func()) error {
+	return s.Write(ctx, items)
+}
@@ -0,0 +1,190 @@
+package sinks_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/cyucelen/walker"
+	"github.com/cyucelen/walker/sinks"
+	_ "github.com/glebarez/go-sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	ID       int
+	UserName string
+	Email    *string `db:"mail"`
+	Ignored  string  `db:"-"`
+}
+
+func openDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", ":memory:")
+	assert.Nil(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, user_name TEXT NOT NULL, mail TEXT)")
+	assert.Nil(t, err)
+	return db
+}
+
+func userNames(t *testing.T, db *sql.DB) map[int]string {
+	rows, err := db.Query("SELECT id, user_name FROM users")
+	assert.Nil(t, err)
+	defer rows.Close()
+
+	names := map[int]string{}
+	for rows.Next() {
+		var id int
+		var name string
+		assert.Nil(t, rows.Scan(&id, &name))
+		names[id] = name
+	}
+	return names
+}
+
+func TestSQLInsertsPagesOfWalk(t *testing.T) {
+	db := openDB(t)
+	sink, err := sinks.NewSQL[user](db, sinks.SQLConfig{Table: "users", Dialect: sinks.SQLite, RowsPerInsert: 3})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"id", "user_name", "mail"}, sink.Columns())
+
+	source := func(start, fetchCount int) ([]user, error) {
+		users := make([]user, 0, fetchCount)
+		for id := start; id < start+fetchCount; id++ {
+			users = append(users, user{ID: id, UserName: "user"})
+		}
+		return users, nil
+	}
+
+	w := walker.NewCtx(
+		walker.AdaptSource(source),
+		sink.Sink,
+		walker.WithLimiter(walker.ConstantLimiter(50)),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(2),
+	)
+	w.Walk()
+
+	assert.Empty(t, w.FailedTasks())
+	assert.Len(t, userNames(t, db), 50)
+}
+
+func TestSQLUpsertsOnConflict(t *testing.T) {
+	db := openDB(t)
+	sink, err := sinks.NewSQL[*user](db, sinks.SQLConfig{Table: "users", Dialect: sinks.SQLite, ConflictColumns: []string{"id"}})
+	assert.Nil(t, err)
+
+	mail := "first@example.com"
+	assert.Nil(t, sink.Write(context.Background(), []*user{{ID: 1, UserName: "first", Email: &mail}, {ID: 2, UserName: "second"}}))
+	assert.Nil(t, sink.Write(context.Background(), []*user{{ID: 1, UserName: "renamed"}}))
+
+	assert.Equal(t, map[int]string{1: "renamed", 2: "second"}, userNames(t, db))
+}
+
+func TestSQLRollsBackFailedBatchAndReportsFailedTask(t *testing.T) {
+	db := openDB(t)
+	sink, err := sinks.NewSQL[user](db, sinks.SQLConfig{Table: "users", Dialect: sinks.SQLite, RowsPerInsert: 1})
+	assert.Nil(t, err)
+
+	source := func(start, fetchCount int) ([]user, error) {
+		if start == 1 {
+			return []user{{ID: 10, UserName: "valid"}, {ID: 10, UserName: "duplicate"}}, nil
+		}
+		return []user{{ID: start, UserName: "user"}}, nil
+	}
+
+	w := walker.NewCtx(
+		walker.AdaptSource(source),
+		sink.Sink,
+		walker.WithLimiter(walker.ConstantLimiter(3)),
+		walker.WithMaxBatchSize(1),
+		walker.WithPagination(walker.CursorPagination{}),
+		walker.WithParallelism(1),
+	)
+	w.Walk()
+
+	failed := w.FailedTasks()
+	assert.Len(t, failed, 1)
+	assert.Equal(t, 1, failed[0].Start)
+	assert.Equal(t, map[int]string{0: "user", 2: "user"}, userNames(t, db))
+}
+
+func TestSQLRejectsNilItems(t *testing.T) {
+	db := openDB(t)
+	sink, err := sinks.NewSQL[*user](db, sinks.SQLConfig{Table: "users", Dialect: sinks.SQLite})
+	assert.Nil(t, err)
+
+	err = sink.Write(context.Background(), []*user{{ID: 1, UserName: "user"}, nil})
+	assert.ErrorIs(t, err, sinks.ErrNilItem)
+	assert.Empty(t, userNames(t, db))
+}
+
+type Account struct {
+	Mail *string `db:"mail"`
+}
+
+type accountUser struct {
+	ID       int
+	UserName string
+	*Account
+}
+
+func TestSQLWritesNullForNilEmbeddedStructs(t *testing.T) {
+	db := openDB(t)
+	sink, err := sinks.NewSQL[accountUser](db, sinks.SQLConfig{Table: "users", Dialect: sinks.SQLite})
+	assert.Nil(t, err)
+
+	mail := "b@example.com"
+	err = sink.Write(context.Background(), []accountUser{{ID: 1, UserName: "a"}, {ID: 2, UserName: "b", Account: &Account{Mail: &mail}}})
+	assert.Nil(t, err)
+
+	var mails []sql.NullString
+	rows, err := db.Query("SELECT mail FROM users ORDER BY id")
+	assert.Nil(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var mail sql.NullString
+		assert.Nil(t, rows.Scan(&mail))
+		mails = append(mails, mail)
+	}
+	assert.Equal(t, []sql.NullString{{}, {String: mail, Valid: true}}, mails)
+}
+
+type orderLine struct {
+	Order int `db:"order"`
+	Group string
+	User  string `db:"User"`
+}
+
+func TestSQLQuotesReservedAndMixedCaseIdentifiers(t *testing.T) {
+	db := openDB(t)
+	_, err := db.Exec(`CREATE TABLE "order lines" ("order" INTEGER PRIMARY KEY, "group" TEXT, "User" TEXT)`)
+	assert.Nil(t, err)
+
+	sink, err := sinks.NewSQL[orderLine](db, sinks.SQLConfig{Table: "order lines", Dialect: sinks.SQLite, ConflictColumns: []string{"order"}})
+	assert.Nil(t, err)
+
+	assert.Nil(t, sink.Write(context.Background(), []orderLine{{Order: 1, Group: "a", User: "x"}}))
+	assert.Nil(t, sink.Write(context.Background(), []orderLine{{Order: 1, Group: "b", User: "y"}}))
+
+	var group, name string
+	assert.Nil(t, db.QueryRow(`SELECT "group", "User" FROM "order lines" WHERE "order" = 1`).Scan(&group, &name))
+	assert.Equal(t, "b", group)
+	assert.Equal(t, "y", name)
+}
+
+func TestDialectsQuoteIdentifiers(t *testing.T) {
+	assert.Equal(t, `"order"`, sinks.Postgres.Quote("order"))
+	assert.Equal(t, `"say ""hi"""`, sinks.SQLite.Quote(`say "hi"`))
+	assert.Equal(t, "`group`", sinks.MySQL.Quote("group"))
+	assert.Equal(t, "`a``b`", sinks.MySQL.Quote("a`b"))
+}
+
+func TestPostgresAndMySQLUpsertClauses(t *testing.T) {
+	assert.Equal(t, "$2", sinks.Postgres.Placeholder(2))
+	assert.Equal(t, " ON CONFLICT (id) DO UPDATE SET name = excluded.name", sinks.Postgres.Upsert([]string{"id"}, []string{"name"}))
+	assert.Equal(t, " ON CONFLICT (id) DO NOTHING", sinks.Postgres.Upsert([]string{"id"}, nil))
+	assert.Equal(t, " ON DUPLICATE KEY UPDATE name = VALUES(name)", sinks.MySQL.Upsert([]string{"id"}, []string{"name"}))
+}
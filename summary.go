@@ -0,0 +1,64 @@
+package walker
+
+import "sync/atomic"
+
+type Summary struct {
+	SourceCalls     int64
+	BytesRead       int64
+	CacheHits       int64
+	UniqueItems     int64
+	Duplicates      int64
+	FailedTasks     int
+	Stopped         bool
+	BudgetExhausted bool
+	RemainingCalls  int64
+	RemainingBytes  int64
+}
+
+type stats struct {
+	sourceCalls int64
+	cacheHits   int64
+	uniqueItems int64
+	duplicates  int64
+}
+
+func (w *Walker[T]) Summary() Summary {
+	w.failedTasksMutex.Lock()
+	failedTasks := len(w.failedTasks)
+	w.failedTasksMutex.Unlock()
+
+	return Summary{
+		SourceCalls:     atomic.LoadInt64(&w.stats.sourceCalls),
+		BytesRead:       atomic.LoadInt64(&w.budget.usedBytes),
+		CacheHits:       atomic.LoadInt64(&w.stats.cacheHits),
+		UniqueItems:     atomic.LoadInt64(&w.stats.uniqueItems),
+		Duplicates:      atomic.LoadInt64(&w.stats.duplicates),
+		FailedTasks:     failedTasks,
+		Stopped:         w.IsStopped(),
+		BudgetExhausted: w.budget.exhausted(),
+		RemainingCalls:  w.budget.remainingCalls(),
+		RemainingBytes:  w.budget.remainingBytes(),
+	}
+}
+
+func (s Summary) add(other Summary) Summary {
+	return Summary{
+		SourceCalls:     s.SourceCalls + other.SourceCalls,
+		BytesRead:       s.BytesRead + other.BytesRead,
+		CacheHits:       s.CacheHits + other.CacheHits,
+		UniqueItems:     s.UniqueItems + other.UniqueItems,
+		Duplicates:      s.Duplicates + other.Duplicates,
+		FailedTasks:     s.FailedTasks + other.FailedTasks,
+		Stopped:         s.Stopped || other.Stopped,
+		BudgetExhausted: s.BudgetExhausted || other.BudgetExhausted,
+		RemainingCalls:  addRemaining(s.RemainingCalls, other.RemainingCalls),
+		RemainingBytes:  addRemaining(s.RemainingBytes, other.RemainingBytes),
+	}
+}
+
+func addRemaining(a, b int64) int64 {
+	if a == Unlimited || b == Unlimited {
+		return Unlimited
+	}
+	return a + b
+}
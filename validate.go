@@ -0,0 +1,118 @@
+package walker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidOption = errors.New("walker: invalid option")
+	ErrNilSource     = errors.New("walker: source must not be nil")
+	ErrNilSink       = errors.New("walker: sink must not be nil")
+	ErrNilExtractor  = errors.New("walker: extractor must not be nil")
+)
+
+type ValidationError []error
+
+func (v ValidationError) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, err := range v {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (v ValidationError) Is(target error) bool {
+	for _, err := range v {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v ValidationError) As(target any) bool {
+	for _, err := range v {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func invalidOption(option, format string, args ...any) error {
+	return fmt.Errorf("%w: %s %s", ErrInvalidOption, option, fmt.Sprintf(format, args...))
+}
+
+func Validate(options ...Option) error {
+	config := newConfig(options...)
+	defer config.contextCancel()
+
+	if errs := config.validate(); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func newValidConfig(errs ValidationError, options ...Option) (*config, error) {
+	config := newConfig(options...)
+	errs = append(errs, config.validate()...)
+	if len(errs) > 0 {
+		config.contextCancel()
+		return nil, errs
+	}
+	return config, nil
+}
+
+func (c *config) rateLimitErrors() ValidationError {
+	errs := append(ValidationError{}, c.invalid...)
+	limiters := append([]RateLimiter{}, c.rateLimiters...)
+	for _, shared := range c.sharedRateLimiters {
+		if shared != nil {
+			limiters = append(limiters, shared.limiter)
+		}
+	}
+
+	for _, limiter := range limiters {
+		if validator, ok := limiter.(Validator); ok {
+			if err := validator.Validate(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs
+}
+
+func (c *config) validate() ValidationError {
+	errs := c.rateLimitErrors()
+	check := func(valid bool, option, format string, args ...any) {
+		if !valid {
+			errs = append(errs, invalidOption(option, format, args...))
+		}
+	}
+
+	check(c.maxBatchSize > 0, "WithMaxBatchSize", "must be positive, got %d", c.maxBatchSize)
+	check(c.parallelism > 0, "WithParallelism", "must be positive, got %d", c.parallelism)
+	check(c.limiter != nil, "WithLimiter", "must not be nil")
+	check(c.pagination != nil, "WithPagination", "must not be nil")
+	check(c.taskTimeout >= 0, "WithTaskTimeout", "must not be negative, got %s", c.taskTimeout)
+	check(c.retries.attempts >= 0 && c.retries.backoff >= 0, "WithRetries", "attempts and backoff must not be negative, got %d and %s", c.retries.attempts, c.retries.backoff)
+	check(c.budget.calls >= 0 || c.budget.calls == Unlimited, "WithBudget", "must not be negative, got %d", c.budget.calls)
+	check(c.budget.bytes >= 0 || c.budget.bytes == Unlimited, "WithByteBudget", "must not be negative, got %d", c.budget.bytes)
+	check(c.hostConcurrency >= 0, "WithPerHostConcurrency", "must not be negative, got %d", c.hostConcurrency)
+	if c.hedging != nil {
+		check(c.hedging.percentile > 0 && c.hedging.percentile <= 100, "WithHedging", "percentile must be in (0, 100], got %g", c.hedging.percentile)
+	}
+	for _, limiter := range c.rateLimiters {
+		check(limiter != nil, "WithRateLimiter", "must not be nil")
+	}
+	for _, shared := range c.sharedRateLimiters {
+		check(shared != nil, "WithSharedRateLimiter", "must not be nil")
+	}
+	for _, flusher := range c.flushers {
+		check(flusher != nil, "WithFlusher", "must not be nil")
+	}
+
+	return errs
+}
@@ -0,0 +1,126 @@
+package walker_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRejectsInvalidOptions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		options []walker.Option
+		err     string
+	}{
+		{name: "parallelism", options: []walker.Option{walker.WithParallelism(0)}, err: "walker: invalid option: WithParallelism must be positive, got 0"},
+		{name: "batch size", options: []walker.Option{walker.WithMaxBatchSize(0)}, err: "walker: invalid option: WithMaxBatchSize must be positive, got 0"},
+		{name: "rate limit", options: []walker.Option{walker.WithRateLimit(-5, time.Second)}, err: "walker: invalid option: WithRateLimit count and per must be positive, got -5 per 1s"},
+		{name: "per host rate limit", options: []walker.Option{walker.WithPerHostRateLimit(5, 0)}, err: "walker: invalid option: WithPerHostRateLimit count and per must be positive, got 5 per 0s"},
+		{name: "limiter", options: []walker.Option{walker.WithLimiter(nil)}, err: "walker: invalid option: WithLimiter must not be nil"},
+		{name: "budget", options: []walker.Option{walker.WithBudget(-2)}, err: "walker: invalid option: WithBudget must not be negative, got -2"},
+		{name: "hedging", options: []walker.Option{walker.WithHedging(150)}, err: "walker: invalid option: WithHedging percentile must be in (0, 100], got 150"},
+		{name: "shared rate limiter", options: []walker.Option{walker.WithSharedRateLimiter(nil)}, err: "walker: invalid option: WithSharedRateLimiter must not be nil"},
+		{name: "flusher", options: []walker.Option{walker.WithFlusher(nil)}, err: "walker: invalid option: WithFlusher must not be nil"},
+		{name: "retries", options: []walker.Option{walker.WithRetries(-1, 0)}, err: "walker: invalid option: WithRetries attempts and backoff must not be negative, got -1 and 0s"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := walker.Validate(tc.options...)
+			assert.EqualError(t, err, tc.err)
+			assert.ErrorIs(t, err, walker.ErrInvalidOption)
+		})
+	}
+}
+
+func TestValidateAcceptsDefaultsAndValidOptions(t *testing.T) {
+	assert.Nil(t, walker.Validate())
+	assert.Nil(t, walker.Validate(
+		walker.WithParallelism(2),
+		walker.WithMaxBatchSize(5),
+		walker.WithRateLimit(10, time.Second),
+		walker.WithBudget(walker.Unlimited),
+		walker.WithBudget(0),
+	))
+}
+
+func TestNewEReportsAllErrors(t *testing.T) {
+	w, err := walker.NewE[[]int](nil, nil, walker.WithParallelism(0), walker.WithMaxBatchSize(-1))
+
+	assert.Nil(t, w)
+	assert.ErrorIs(t, err, walker.ErrNilSource)
+	assert.ErrorIs(t, err, walker.ErrNilSink)
+	assert.ErrorIs(t, err, walker.ErrInvalidOption)
+
+	var errs walker.ValidationError
+	assert.ErrorAs(t, err, &errs)
+	assert.Len(t, errs, 4)
+	assert.True(t, strings.HasPrefix(err.Error(), "walker: source must not be nil; walker: sink must not be nil; "))
+}
+
+func TestNewERejectsNilSharedRateLimiterAndFlusher(t *testing.T) {
+	mockSink := &MockSink{}
+
+	w, err := walker.NewE(cursorSource(10), mockSink.sink, walker.WithSharedRateLimiter(nil))
+	assert.Nil(t, w)
+	assert.ErrorIs(t, err, walker.ErrInvalidOption)
+
+	w, err = walker.NewE(cursorSource(10), mockSink.sink, walker.WithFlusher(nil))
+	assert.Nil(t, w)
+	assert.ErrorIs(t, err, walker.ErrInvalidOption)
+}
+
+func TestNewEBuildsWalker(t *testing.T) {
+	mockSink := &MockSink{}
+	w, err := walker.NewE(cursorSource(20), mockSink.sink, walker.WithLimiter(walker.ConstantLimiter(20)), walker.WithPagination(walker.CursorPagination{}))
+	assert.Nil(t, err)
+
+	w.Walk()
+	assert.Len(t, mockSink.sortedResults(), 2)
+}
+
+func TestNewPanicsOnInvalidRateLimits(t *testing.T) {
+	assert.Panics(t, func() {
+		walker.New(cursorSource(10), (&MockSink{}).sink, walker.WithRateLimit(0, time.Second))
+	})
+	assert.Panics(t, func() {
+		walker.NewApiWalker(http.DefaultClient, nil, nil, walker.WithPerHostRateLimit(5, 0))
+	})
+}
+
+func TestNewApiWalkerEReportsAllErrors(t *testing.T) {
+	w, err := walker.NewApiWalkerE(http.DefaultClient, nil, nil, walker.WithRateLimit(0, time.Second))
+
+	assert.Nil(t, w)
+	assert.ErrorIs(t, err, walker.ErrNilSource)
+	assert.ErrorIs(t, err, walker.ErrNilSink)
+	assert.ErrorIs(t, err, walker.ErrInvalidOption)
+}
+
+func TestNewItemWalkerEReportsAllErrors(t *testing.T) {
+	w, err := walker.NewItemWalkerE[page, int](nil, nil, nil, walker.WithParallelism(-1))
+
+	assert.Nil(t, w)
+	assert.ErrorIs(t, err, walker.ErrNilSource)
+	assert.ErrorIs(t, err, walker.ErrNilExtractor)
+	assert.ErrorIs(t, err, walker.ErrNilSink)
+	assert.ErrorIs(t, err, walker.ErrInvalidOption)
+}
+
+func TestValidationErrorMatchesWrappedErrors(t *testing.T) {
+	panicErr := &walker.PanicError{Value: "boom"}
+	err := fmt.Errorf("walk: %w", walker.ValidationError{walker.ErrNilSink, fmt.Errorf("sink: %w", panicErr)})
+
+	assert.True(t, errors.Is(err, walker.ErrNilSink))
+	assert.False(t, errors.Is(err, walker.ErrNilSource))
+
+	var target *walker.PanicError
+	assert.True(t, errors.As(err, &target))
+	assert.Same(t, panicErr, target)
+}
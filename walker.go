@@ -5,13 +5,15 @@ import (
 	"runtime"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/alitto/pond"
-	"go.uber.org/ratelimit"
 )
 
 type Source[T any] func(start, fetchCount int) (T, error)
 type Sink[T any] func(result T, stop func()) error
+type SourceCtx[T any] func(ctx context.Context, start, fetchCount int) (T, error)
+type SinkCtx[T any] func(ctx context.Context, result T, stop func()) error
 type Limiter func() int
 
 type Pagination interface {
@@ -19,31 +21,115 @@ type Pagination interface {
 	FetchCount(limit, start, batchSize int) int
 }
 
+type Task struct {
+	Start      int
+	FetchCount int
+}
+
+type taskKey struct{}
+
+func TaskFromContext(ctx context.Context) (Task, bool) {
+	task, ok := ctx.Value(taskKey{}).(Task)
+	return task, ok
+}
+
+type failureReporter func(itemIndex int, err error)
+
+type reporterKey struct{}
+
+func ReportFailure(ctx context.Context, itemIndex int, err error) bool {
+	report, ok := ctx.Value(reporterKey{}).(failureReporter)
+	if ok {
+		report(itemIndex, err)
+	}
+	return ok
+}
+
+const NoItemIndex = -1
+
 type FailedTask struct {
 	Start      int
 	FetchCount int
+	ItemIndex  int
 	Err        error
 }
 
 type Walker[T any] struct {
-	source           Source[T]
-	sink             Sink[T]
+	source           SourceCtx[T]
+	sink             SinkCtx[T]
 	isStopped        int32
-	rateLimiter      ratelimit.Limiter
+	stopped          chan struct{}
+	stopOnce         sync.Once
+	walking          int32
+	finished         chan struct{}
+	finishOnce       sync.Once
+	pauser           pauser
+	tasks            taskTracker
+	latencies        latencyTracker
+	discard          func(T)
+	retryResult      func(T) bool
+	retryAfter       func(T) time.Duration
+	skipFailedPages  bool
+	rateLimiter      RateLimiter
+	sharedClients    []sharedClient
 	sourcePool       *pond.WorkerPool
 	sinkPool         *pond.WorkerPool
 	failedTasks      []FailedTask
 	failedTasksMutex sync.Mutex
+	panicErr         *PanicError
+	panicOnce        sync.Once
+	scope            *walkScope
+	parent           *walkScope
+	spawner          Task
+	children         sync.WaitGroup
 	*config
 }
 
 func New[T any](source Source[T], sink Sink[T], options ...Option) *Walker[T] {
+	return NewCtx(AdaptSource(source), AdaptSink(sink), options...)
+}
+
+func NewCtx[T any](source SourceCtx[T], sink SinkCtx[T], options ...Option) *Walker[T] {
+	return newWalker(source, sink, newConfig(options...))
+}
+
+func NewE[T any](source Source[T], sink Sink[T], options ...Option) (*Walker[T], error) {
+	var sourceCtx SourceCtx[T]
+	if source != nil {
+		sourceCtx = AdaptSource(source)
+	}
+	var sinkCtx SinkCtx[T]
+	if sink != nil {
+		sinkCtx = AdaptSink(sink)
+	}
+	return NewCtxE(sourceCtx, sinkCtx, options...)
+}
+
+func NewCtxE[T any](source SourceCtx[T], sink SinkCtx[T], options ...Option) (*Walker[T], error) {
+	errs := ValidationError{}
+	if source == nil {
+		errs = append(errs, ErrNilSource)
+	}
+	if sink == nil {
+		errs = append(errs, ErrNilSink)
+	}
+
+	config, err := newValidConfig(errs, options...)
+	if err != nil {
+		return nil, err
+	}
+	return newWalker(source, sink, config), nil
+}
+
+func newConfig(options ...Option) *config {
 	config := &config{
 		maxBatchSize: 10,
 		parallelism:  runtime.NumCPU(),
 		limiter:      InfiniteLimiter(),
 		pagination:   OffsetPagination{},
-		rateLimit:    defaultRateLimiter,
+		panicPolicy:  PanicPolicyContinue,
+		budget:       newBudget(),
+		stats:        &stats{},
 	}
 
 	for _, option := range options {
@@ -54,32 +140,64 @@ func New[T any](source Source[T], sink Sink[T], options ...Option) *Walker[T] {
 		WithContext(context.Background())(config)
 	}
 
-	sourcePoolBuffer := 0
-	sinkPoolBuffer := 0
-	sourcePool := pond.New(config.parallelism, sourcePoolBuffer, pond.MinWorkers(config.parallelism), pond.Context(config.context))
-	sinkPool := pond.New(config.parallelism*2, sinkPoolBuffer, pond.Context(config.context))
+	return config
+}
+
+func newWalker[T any](source SourceCtx[T], sink SinkCtx[T], config *config) *Walker[T] {
+	if errs := config.rateLimitErrors(); len(errs) > 0 {
+		config.contextCancel()
+		panic(errs)
+	}
 
 	walker := &Walker[T]{
 		config:      config,
 		source:      source,
 		sink:        sink,
-		rateLimiter: ratelimit.NewUnlimited(),
-		sourcePool:  sourcePool,
-		sinkPool:    sinkPool,
+		rateLimiter: UnlimitedRateLimiter(),
+		sourcePool:  newSourcePool(config),
+		sinkPool:    newSinkPool(config),
 		failedTasks: make([]FailedTask, 0),
+		stopped:     make(chan struct{}),
+		finished:    make(chan struct{}),
 	}
-
-	if config.rateLimit != defaultRateLimiter {
-		walker.rateLimiter = ratelimit.New(config.rateLimit.count, ratelimit.Per(config.rateLimit.per))
+	rateLimiters := append([]RateLimiter{}, config.rateLimiters...)
+	for _, shared := range config.sharedRateLimiters {
+		client := shared.Client()
+		walker.sharedClients = append(walker.sharedClients, sharedClient{shared: shared, client: client})
+		rateLimiters = append(rateLimiters, client)
 	}
+	walker.rateLimiter = CombineRateLimiters(rateLimiters...)
+	walker.scope = newWalkScope(walker)
 
 	return walker
 }
 
+func newSourcePool(config *config) *pond.WorkerPool {
+	sourcePoolBuffer := 0
+	return pond.New(config.parallelism, sourcePoolBuffer, pond.MinWorkers(config.parallelism), pond.Context(config.context))
+}
+
+func newSinkPool(config *config) *pond.WorkerPool {
+	sinkPoolBuffer := 0
+	return pond.New(config.parallelism*2, sinkPoolBuffer, pond.Context(config.context))
+}
+
 func (w *Walker[T]) Walk() {
+	atomic.StoreInt32(&w.walking, 1)
+	defer w.finishOnce.Do(func() {
+		close(w.finished)
+	})
+
 	w.submitTasks()
 	w.sourcePool.StopAndWait()
 	w.sinkPool.StopAndWait()
+	w.children.Wait()
+	w.flush()
+	w.releaseSharedClients()
+
+	if w.panicErr != nil {
+		panic(w.panicErr)
+	}
 }
 
 func (w *Walker[T]) submitTasks() {
@@ -88,9 +206,14 @@ func (w *Walker[T]) submitTasks() {
 
 	for batchIndex := 0; batchIndex < batch.Count; batchIndex++ {
 		for workerNumber := 0; workerNumber < w.parallelism; workerNumber++ {
-			w.rateLimiter.Take()
+			w.waitWhilePaused()
+
+			if err := w.rateLimiter.Wait(w.context); err != nil || w.IsStopped() {
+				return
+			}
 
-			if w.IsStopped() {
+			if w.budget.exhausted() {
+				w.Stop()
 				return
 			}
 
@@ -107,13 +230,59 @@ func (w *Walker[T]) submitTask(start, fetchCount int) {
 		return
 	}
 
+	if !w.budget.takeCall() {
+		w.Stop()
+		return
+	}
+
+	w.tasks.add()
 	w.sourcePool.Submit(func() {
-		result, err := w.source(start, fetchCount)
+		defer w.tasks.done()
+
+		ctx, cancel := w.newTaskContext(start, fetchCount)
+		sinkSubmitted := false
+		defer func() {
+			if !sinkSubmitted {
+				cancel()
+			}
+		}()
+		defer w.recoverPanic(start, fetchCount)
+
+		sourceCtx, cancelSource := w.withTaskTimeout(ctx)
+		defer func() {
+			if !sinkSubmitted {
+				cancelSource()
+			}
+		}()
+
+		result, err := w.fetch(sourceCtx, start, fetchCount)
 		if err != nil {
 			w.storeFailedTask(start, fetchCount, err)
 		}
+
+		if err != nil && w.skipFailedPages {
+			return
+		}
+
+		if w.context.Err() != nil {
+			if err == nil && w.discard != nil {
+				w.discard(result)
+			}
+			return
+		}
+
+		w.tasks.add()
+		sinkSubmitted = true
 		w.sinkPool.Submit(func() {
-			err = w.sink(result, w.Stop)
+			defer w.tasks.done()
+			defer cancel()
+			defer cancelSource()
+			defer w.recoverPanic(start, fetchCount)
+
+			ctx, cancelSink := w.withTaskTimeout(ctx)
+			defer cancelSink()
+
+			err := w.sink(ctx, result, w.Stop)
 			if err != nil {
 				w.storeFailedTask(start, fetchCount, err)
 			}
@@ -121,10 +290,34 @@ func (w *Walker[T]) submitTask(start, fetchCount int) {
 	})
 }
 
+func (w *Walker[T]) newTaskContext(start, fetchCount int) (context.Context, context.CancelFunc) {
+	ctx := context.WithValue(w.context, taskKey{}, Task{Start: start, FetchCount: fetchCount})
+	ctx = context.WithValue(ctx, reporterKey{}, failureReporter(func(itemIndex int, err error) {
+		w.storeFailedItem(start, fetchCount, itemIndex, err)
+	}))
+	ctx = context.WithValue(ctx, scopeKey{}, w.scope)
+	return context.WithCancel(ctx)
+}
+
+func (w *Walker[T]) withTaskTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if w.taskTimeout > 0 {
+		return context.WithTimeout(ctx, w.taskTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
 func (w *Walker[T]) storeFailedTask(start, fetchCount int, err error) {
+	w.storeFailedItem(start, fetchCount, NoItemIndex, err)
+}
+
+func (w *Walker[T]) storeFailedItem(start, fetchCount, itemIndex int, err error) {
 	w.failedTasksMutex.Lock()
-	defer w.failedTasksMutex.Unlock()
-	w.failedTasks = append(w.failedTasks, FailedTask{Start: start, FetchCount: fetchCount, Err: err})
+	w.failedTasks = append(w.failedTasks, FailedTask{Start: start, FetchCount: fetchCount, ItemIndex: itemIndex, Err: err})
+	w.failedTasksMutex.Unlock()
+
+	if w.parent != nil {
+		w.parent.storeFailedItem(start, fetchCount, itemIndex, &SpawnedWalkError{Spawner: w.spawner, Err: err})
+	}
 }
 
 func (w *Walker[T]) FailedTasks() []FailedTask {
@@ -133,8 +326,23 @@ func (w *Walker[T]) FailedTasks() []FailedTask {
 
 func (w *Walker[T]) Stop() {
 	atomic.StoreInt32(&w.isStopped, 1)
+	w.stopOnce.Do(func() {
+		close(w.stopped)
+	})
 }
 
 func (w *Walker[T]) IsStopped() bool {
-	return atomic.LoadInt32(&w.isStopped) == 1
+	return atomic.LoadInt32(&w.isStopped) == 1 || (w.parent != nil && w.parent.isStopped())
+}
+
+func AdaptSource[T any](source Source[T]) SourceCtx[T] {
+	return func(_ context.Context, start, fetchCount int) (T, error) {
+		return source(start, fetchCount)
+	}
+}
+
+func AdaptSink[T any](sink Sink[T]) SinkCtx[T] {
+	return func(_ context.Context, result T, stop func()) error {
+		return sink(result, stop)
+	}
 }
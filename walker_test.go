@@ -1,9 +1,13 @@
 package walker_test
 
 import (
+	"context"
+	"errors"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/cyucelen/walker"
 	"github.com/samber/lo"
@@ -425,3 +429,150 @@ func min(a, b int) int {
 func isEmpty(result []int) bool {
 	return len(result) == 0
 }
+
+func TestWalkerPassesTaskContextToSourceAndSink(t *testing.T) {
+	var sourceCtx, sinkCtx context.Context
+	source := func(ctx context.Context, start, fetchCount int) ([]int, error) {
+		sourceCtx = ctx
+		return []int{start}, nil
+	}
+	sink := func(ctx context.Context, result []int, stop func()) error {
+		sinkCtx = ctx
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline)
+		assert.NoError(t, ctx.Err())
+		return nil
+	}
+
+	w := walker.NewCtx(
+		source,
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(1)),
+		walker.WithParallelism(1),
+		walker.WithTaskTimeout(time.Minute),
+	)
+	w.Walk()
+
+	sourceTask, _ := walker.TaskFromContext(sourceCtx)
+	sinkTask, _ := walker.TaskFromContext(sinkCtx)
+	assert.Equal(t, walker.Task{Start: 0, FetchCount: 10}, sourceTask)
+	assert.Equal(t, sourceTask, sinkTask)
+	assert.ErrorIs(t, sourceCtx.Err(), context.Canceled)
+	assert.ErrorIs(t, sinkCtx.Err(), context.Canceled)
+}
+
+func TestWalkerTaskTimeoutStartsAgainForSink(t *testing.T) {
+	var sinkErr error
+	source := func(ctx context.Context, start, fetchCount int) ([]int, error) {
+		time.Sleep(30 * time.Millisecond)
+		return []int{start}, nil
+	}
+	sink := func(ctx context.Context, result []int, stop func()) error {
+		select {
+		case <-time.After(30 * time.Millisecond):
+		case <-ctx.Done():
+			sinkErr = ctx.Err()
+		}
+		return nil
+	}
+
+	w := walker.NewCtx(
+		source,
+		sink,
+		walker.WithLimiter(walker.ConstantLimiter(1)),
+		walker.WithParallelism(1),
+		walker.WithTaskTimeout(50*time.Millisecond),
+	)
+	w.Walk()
+
+	assert.Nil(t, sinkErr)
+	assert.Empty(t, w.FailedTasks())
+}
+
+func TestWalkerTaskTimeoutCancelsSource(t *testing.T) {
+	source := func(ctx context.Context, start, fetchCount int) ([]int, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	w := walker.NewCtx(
+		source,
+		walker.AdaptSink((&MockSink{}).sink),
+		walker.WithLimiter(walker.ConstantLimiter(10)),
+		walker.WithParallelism(1),
+		walker.WithTaskTimeout(10*time.Millisecond),
+	)
+	w.Walk()
+
+	assert.Len(t, w.FailedTasks(), 1)
+	assert.ErrorIs(t, w.FailedTasks()[0].Err, context.DeadlineExceeded)
+}
+
+func TestWalkerContextCancellationReachesSource(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	source := func(taskCtx context.Context, start, fetchCount int) ([]int, error) {
+		cancel()
+		<-taskCtx.Done()
+		return nil, taskCtx.Err()
+	}
+
+	w := walker.NewCtx(
+		source,
+		walker.AdaptSink((&MockSink{}).sink),
+		walker.WithParallelism(1),
+		walker.WithContext(ctx),
+	)
+	w.Walk()
+
+	assert.NotEmpty(t, w.FailedTasks())
+	assert.ErrorIs(t, w.FailedTasks()[0].Err, context.Canceled)
+}
+
+func TestWalkerRetriesFailedSourceCalls(t *testing.T) {
+	var mutex sync.Mutex
+	attempts := map[int]int{}
+	source := func(ctx context.Context, start, fetchCount int) ([]int, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		attempts[start]++
+		if start == 1 && attempts[start] < 3 || start == 2 {
+			return nil, errors.New("temporary failure")
+		}
+		return []int{start}, nil
+	}
+
+	w := walker.NewCtx(
+		source,
+		walker.AdaptSink((&MockSink{}).sink),
+		walker.WithLimiter(walker.ConstantLimiter(30)),
+		walker.WithParallelism(3),
+		walker.WithRetries(2, time.Millisecond),
+	)
+	w.Walk()
+
+	assert.Equal(t, map[int]int{0: 1, 1: 3, 2: 3}, attempts)
+	assert.Len(t, w.FailedTasks(), 1)
+	assert.Equal(t, 2, w.FailedTasks()[0].Start)
+	assert.Equal(t, int64(7), w.Summary().SourceCalls)
+}
+
+func TestWalkerRetriesWaitForRateLimiter(t *testing.T) {
+	source := func(ctx context.Context, start, fetchCount int) ([]int, error) {
+		return nil, errors.New("temporary failure")
+	}
+
+	limiter := &countingRateLimiter{}
+	w := walker.NewCtx(
+		source,
+		walker.AdaptSink((&MockSink{}).sink),
+		walker.WithLimiter(walker.ConstantLimiter(10)),
+		walker.WithParallelism(1),
+		walker.WithRateLimiter(limiter),
+		walker.WithRetries(3, 0),
+	)
+	w.Walk()
+
+	assert.Len(t, w.FailedTasks(), 1)
+	assert.Equal(t, int64(4), w.Summary().SourceCalls)
+	assert.Equal(t, int64(4), atomic.LoadInt64(&limiter.waits))
+}
@@ -0,0 +1,269 @@
+package walkerconfig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrMissingRequest = errors.New("walkerconfig: request is not configured")
+
+type Config struct {
+	Pagination  string        `yaml:"pagination"`
+	BatchSize   *int          `yaml:"batch_size"`
+	Parallelism *int          `yaml:"parallelism"`
+	Limit       *int          `yaml:"limit"`
+	RateLimit   *RateLimit    `yaml:"rate_limit"`
+	Retries     *Retries      `yaml:"retries"`
+	TaskTimeout time.Duration `yaml:"task_timeout"`
+	Request     *Request      `yaml:"request"`
+
+	root         *yaml.Node
+	urlTemplate  *template.Template
+	bodyTemplate *template.Template
+}
+
+type RateLimit struct {
+	Count int           `yaml:"count"`
+	Per   time.Duration `yaml:"per"`
+}
+
+type Retries struct {
+	Attempts int           `yaml:"attempts"`
+	Backoff  time.Duration `yaml:"backoff"`
+}
+
+type Request struct {
+	Method  string            `yaml:"method"`
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	Body    string            `yaml:"body"`
+}
+
+type FieldError struct {
+	Field   string
+	Line    int
+	Message string
+}
+
+func (f *FieldError) Error() string {
+	if f.Line > 0 {
+		return fmt.Sprintf("walkerconfig: %s (line %d): %s", f.Field, f.Line, f.Message)
+	}
+	return fmt.Sprintf("walkerconfig: %s: %s", f.Field, f.Message)
+}
+
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+func Parse(data []byte) (*Config, error) {
+	config := &Config{root: &yaml.Node{}}
+	if err := yaml.Unmarshal(data, config.root); err != nil {
+		return nil, fmt.Errorf("walkerconfig: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("walkerconfig: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (c *Config) Validate() error {
+	var errs Errors
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, &FieldError{Field: field, Line: c.line(field), Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch c.Pagination {
+	case "", "offset", "cursor":
+	default:
+		fail("pagination", "must be offset or cursor, got %q", c.Pagination)
+	}
+	if c.BatchSize != nil && *c.BatchSize <= 0 {
+		fail("batch_size", "must be positive, got %d", *c.BatchSize)
+	}
+	if c.Parallelism != nil && *c.Parallelism <= 0 {
+		fail("parallelism", "must be positive, got %d", *c.Parallelism)
+	}
+	if c.Limit != nil && *c.Limit < 0 {
+		fail("limit", "must not be negative, got %d", *c.Limit)
+	}
+	if c.RateLimit != nil {
+		if c.RateLimit.Count <= 0 {
+			fail("rate_limit.count", "must be positive, got %d", c.RateLimit.Count)
+		}
+		if c.RateLimit.Per <= 0 {
+			fail("rate_limit.per", "must be a positive duration, got %s", c.RateLimit.Per)
+		}
+	}
+	if c.Retries != nil {
+		if c.Retries.Attempts < 0 {
+			fail("retries.attempts", "must not be negative, got %d", c.Retries.Attempts)
+		}
+		if c.Retries.Backoff < 0 {
+			fail("retries.backoff", "must not be negative, got %s", c.Retries.Backoff)
+		}
+	}
+	if c.TaskTimeout < 0 {
+		fail("task_timeout", "must not be negative, got %s", c.TaskTimeout)
+	}
+
+	if c.Request != nil {
+		var err error
+		if c.Request.URL == "" {
+			fail("request.url", "is required")
+		} else if c.urlTemplate, err = NewTemplate("url", c.Request.URL); err != nil {
+			fail("request.url", "%v", err)
+		}
+		if c.Request.Body != "" {
+			if c.bodyTemplate, err = NewTemplate("body", c.Request.Body); err != nil {
+				fail("request.body", "%v", err)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (c *Config) line(field string) int {
+	if c.root == nil || len(c.root.Content) == 0 {
+		return 0
+	}
+
+	node, line := c.root.Content[0], 0
+	for _, key := range strings.Split(field, ".") {
+		if node.Kind != yaml.MappingNode {
+			return line
+		}
+
+		found := false
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				node, line, found = node.Content[i+1], node.Content[i].Line, true
+				break
+			}
+		}
+		if !found {
+			return line
+		}
+	}
+	return line
+}
+
+func NewTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(template.FuncMap{
+		"add": func(a, b int) int { return a + b },
+		"mul": func(a, b int) int { return a * b },
+	}).Parse(text)
+}
+
+func (c *Config) Options() []walker.Option {
+	options := []walker.Option{}
+	if c.Pagination == "cursor" {
+		options = append(options, walker.WithPagination(walker.CursorPagination{}))
+	}
+	if c.BatchSize != nil {
+		options = append(options, walker.WithMaxBatchSize(*c.BatchSize))
+	}
+	if c.Parallelism != nil {
+		options = append(options, walker.WithParallelism(*c.Parallelism))
+	}
+	if c.Limit != nil {
+		options = append(options, walker.WithLimiter(walker.ConstantLimiter(*c.Limit)))
+	}
+	if c.RateLimit != nil {
+		options = append(options, walker.WithRateLimit(c.RateLimit.Count, c.RateLimit.Per))
+	}
+	if c.Retries != nil {
+		options = append(options, walker.WithRetries(c.Retries.Attempts, c.Retries.Backoff))
+	}
+	if c.TaskTimeout > 0 {
+		options = append(options, walker.WithTaskTimeout(c.TaskTimeout))
+	}
+	return options
+}
+
+type templateData struct {
+	Start int
+	Count int
+}
+
+func (c *Config) RequestBuilder() (walker.RequestBuilderCtx, error) {
+	if c.Request == nil || c.urlTemplate == nil {
+		return nil, ErrMissingRequest
+	}
+
+	method := c.Request.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	return func(ctx context.Context, start, fetchCount int) (*http.Request, error) {
+		data := templateData{Start: start, Count: fetchCount}
+
+		var url strings.Builder
+		if err := c.urlTemplate.Execute(&url, data); err != nil {
+			return nil, err
+		}
+
+		var body io.Reader = http.NoBody
+		if c.bodyTemplate != nil {
+			var buffer bytes.Buffer
+			if err := c.bodyTemplate.Execute(&buffer, data); err != nil {
+				return nil, err
+			}
+			body = &buffer
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, url.String(), body)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range c.Request.Headers {
+			req.Header.Set(name, value)
+		}
+		return req, nil
+	}, nil
+}
+
+func (c *Config) NewApiWalker(client *http.Client, sink walker.SinkCtx[*http.Response], options ...walker.Option) (*walker.Walker[*http.Response], error) {
+	requestBuilder, err := c.RequestBuilder()
+	if err != nil {
+		return nil, err
+	}
+	return walker.NewApiWalkerCtxE(client, requestBuilder, sink, append(c.Options(), options...)...)
+}
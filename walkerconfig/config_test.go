@@ -0,0 +1,138 @@
+package walkerconfig_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker/walkerconfig"
+	"github.com/cyucelen/walker/walkertest"
+	"github.com/stretchr/testify/assert"
+)
+
+const walkYAML = `
+pagination: cursor
+batch_size: 20
+parallelism: 2
+limit: 55
+rate_limit:
+  count: 1000
+  per: 1s
+retries:
+  attempts: 2
+  backoff: 5ms
+task_timeout: 10s
+request:
+  method: POST
+  url: "{{.Server}}/items?start={{.Start}}&count={{.Count}}"
+  headers:
+    Accept: application/json
+  body: '{"from": {{.Start}}}'
+`
+
+func TestParseMapsDocumentOntoFields(t *testing.T) {
+	config, err := walkerconfig.Parse([]byte(`
+pagination: cursor
+batch_size: 20
+rate_limit: {count: 10, per: 1m}
+retries: {attempts: 3, backoff: 100ms}
+request:
+  url: "https://api.example.com/items?page={{add .Start 1}}"
+`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "cursor", config.Pagination)
+	assert.Equal(t, 20, *config.BatchSize)
+	assert.Nil(t, config.Parallelism)
+	assert.Equal(t, &walkerconfig.RateLimit{Count: 10, Per: time.Minute}, config.RateLimit)
+	assert.Equal(t, &walkerconfig.Retries{Attempts: 3, Backoff: 100 * time.Millisecond}, config.Retries)
+	assert.Len(t, config.Options(), 4)
+
+	requestBuilder, err := config.RequestBuilder()
+	assert.Nil(t, err)
+	req, err := requestBuilder(context.Background(), 4, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, http.MethodGet, req.Method)
+	assert.Equal(t, "https://api.example.com/items?page=5", req.URL.String())
+}
+
+func TestParseAcceptsJSON(t *testing.T) {
+	config, err := walkerconfig.Parse([]byte(`{"pagination": "offset", "parallelism": 4, "task_timeout": "30s"}`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 4, *config.Parallelism)
+	assert.Equal(t, 30*time.Second, config.TaskTimeout)
+	assert.Len(t, config.Options(), 2)
+
+	_, err = config.RequestBuilder()
+	assert.ErrorIs(t, err, walkerconfig.ErrMissingRequest)
+}
+
+func TestValidationErrorsPointAtFields(t *testing.T) {
+	_, err := walkerconfig.Parse([]byte(`
+pagination: pages
+parallelism: 0
+rate_limit:
+  count: 10
+  per: -1s
+request:
+  url: "{{.Start"
+`))
+
+	var errs walkerconfig.Errors
+	assert.ErrorAs(t, err, &errs)
+	assert.Len(t, errs, 4)
+	assert.EqualError(t, errs[0], `walkerconfig: pagination (line 2): must be offset or cursor, got "pages"`)
+	assert.EqualError(t, errs[1], "walkerconfig: parallelism (line 3): must be positive, got 0")
+	assert.EqualError(t, errs[2], "walkerconfig: rate_limit.per (line 6): must be a positive duration, got -1s")
+	assert.Equal(t, "request.url", errs[3].Field)
+	assert.Equal(t, 8, errs[3].Line)
+}
+
+func TestParseRejectsUnknownAndMistypedFields(t *testing.T) {
+	_, err := walkerconfig.Parse([]byte("parallelism: 2\nbatchsize: 10\n"))
+	assert.ErrorContains(t, err, "line 2: field batchsize not found")
+
+	_, err = walkerconfig.Parse([]byte("parallelism: many\n"))
+	assert.ErrorContains(t, err, "line 1: cannot unmarshal !!str `many` into int")
+}
+
+func TestLoadedConfigWalksAPI(t *testing.T) {
+	server := walkertest.NewServer(walkertest.WithStyle(walkertest.CursorStyle), walkertest.WithTotal(1000))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "walk.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte(strings.ReplaceAll(walkYAML, "{{.Server}}", server.URL)), 0o644))
+
+	config, err := walkerconfig.Load(path)
+	assert.Nil(t, err)
+
+	var mutex sync.Mutex
+	ids := []int{}
+	sink := func(ctx context.Context, res *http.Response, stop func()) error {
+		page, err := walkertest.DecodePage(res)
+		if err != nil {
+			return err
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, item := range page.Items {
+			ids = append(ids, item.ID)
+		}
+		return nil
+	}
+
+	w, err := config.NewApiWalker(http.DefaultClient, sink)
+	assert.Nil(t, err)
+	w.Walk()
+
+	assert.Empty(t, w.FailedTasks())
+	assert.Len(t, ids, 55)
+	assert.Equal(t, int64(3), w.Summary().SourceCalls)
+}
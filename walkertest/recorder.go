@@ -0,0 +1,270 @@
+package walkertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cyucelen/walker"
+)
+
+type Mode int
+
+const (
+	ModeReplay Mode = iota
+	ModeRecord
+)
+
+var ErrInteractionNotFound = errors.New("walkertest: no recorded interaction for request")
+
+type Interaction struct {
+	Start      int           `json:"start"`
+	FetchCount int           `json:"fetch_count"`
+	HasTask    bool          `json:"has_task"`
+	Method     string        `json:"method"`
+	URL        string        `json:"url"`
+	StatusCode int           `json:"status_code,omitempty"`
+	Header     http.Header   `json:"header,omitempty"`
+	Body       string        `json:"body,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Latency    time.Duration `json:"latency"`
+}
+
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type failure struct {
+	err        error
+	statusCode int
+}
+
+type RecorderOption func(*Recorder)
+
+type Recorder struct {
+	path            string
+	mode            Mode
+	transport       http.RoundTripper
+	simulateLatency bool
+	fixedLatency    time.Duration
+	failures        map[walker.Task]failure
+	mutex           sync.Mutex
+	cassette        Cassette
+	replayed        []bool
+}
+
+func WithTransport(transport http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+func WithRecordedLatency() RecorderOption {
+	return func(r *Recorder) {
+		r.simulateLatency = true
+	}
+}
+
+func WithLatency(latency time.Duration) RecorderOption {
+	return func(r *Recorder) {
+		r.fixedLatency = latency
+	}
+}
+
+func WithError(start, fetchCount int, err error) RecorderOption {
+	return func(r *Recorder) {
+		r.failures[walker.Task{Start: start, FetchCount: fetchCount}] = failure{err: err}
+	}
+}
+
+func WithStatus(start, fetchCount, statusCode int) RecorderOption {
+	return func(r *Recorder) {
+		r.failures[walker.Task{Start: start, FetchCount: fetchCount}] = failure{statusCode: statusCode}
+	}
+}
+
+func NewRecorder(path string, mode Mode, options ...RecorderOption) (*Recorder, error) {
+	recorder := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		failures:  make(map[walker.Task]failure),
+	}
+
+	for _, option := range options {
+		option(recorder)
+	}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &recorder.cassette); err != nil {
+			return nil, err
+		}
+		recorder.replayed = make([]bool, len(recorder.cassette.Interactions))
+	}
+
+	return recorder, nil
+}
+
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func (r *Recorder) Cassette() Cassette {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return Cassette{Interactions: append([]Interaction{}, r.cassette.Interactions...)}
+}
+
+func (r *Recorder) Save() error {
+	data, err := json.MarshalIndent(r.Cassette(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o644)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	task, hasTask := walker.TaskFromContext(req.Context())
+	if hasTask {
+		if failure, ok := r.failures[task]; ok {
+			return failure.response(req)
+		}
+	}
+
+	if r.mode == ModeRecord {
+		return r.record(req, task, hasTask)
+	}
+
+	return r.replay(req, task, hasTask)
+}
+
+func (r *Recorder) record(req *http.Request, task walker.Task, hasTask bool) (*http.Response, error) {
+	interaction := Interaction{
+		Start:      task.Start,
+		FetchCount: task.FetchCount,
+		HasTask:    hasTask,
+		Method:     req.Method,
+		URL:        req.URL.String(),
+	}
+
+	began := time.Now()
+	res, err := r.transport.RoundTrip(req)
+	if err == nil {
+		var body []byte
+		body, err = io.ReadAll(res.Body)
+		res.Body.Close()
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		interaction.StatusCode = res.StatusCode
+		interaction.Header = res.Header.Clone()
+		interaction.Body = string(body)
+	}
+	interaction.Latency = time.Since(began)
+	if err != nil {
+		interaction.Error = err.Error()
+	}
+
+	r.mutex.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mutex.Unlock()
+
+	return res, err
+}
+
+func (r *Recorder) replay(req *http.Request, task walker.Task, hasTask bool) (*http.Response, error) {
+	interaction, ok := r.find(req, task, hasTask)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s", ErrInteractionNotFound, req.Method, req.URL)
+	}
+
+	latency := r.fixedLatency
+	if r.simulateLatency {
+		latency = interaction.Latency
+	}
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	if interaction.Error != "" {
+		return nil, errors.New(interaction.Error)
+	}
+
+	return &http.Response{
+		Status:        http.StatusText(interaction.StatusCode),
+		StatusCode:    interaction.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader([]byte(interaction.Body))),
+		ContentLength: int64(len(interaction.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) find(req *http.Request, task walker.Task, hasTask bool) (Interaction, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	last := -1
+	for index, interaction := range r.cassette.Interactions {
+		if !interaction.matches(req, task, hasTask) {
+			continue
+		}
+		if !r.replayed[index] {
+			r.replayed[index] = true
+			return interaction, true
+		}
+		last = index
+	}
+
+	if last >= 0 {
+		return r.cassette.Interactions[last], true
+	}
+	return Interaction{}, false
+}
+
+func (i Interaction) matches(req *http.Request, task walker.Task, hasTask bool) bool {
+	if i.Method != req.Method || i.HasTask != hasTask {
+		return false
+	}
+	if hasTask && (i.Start != task.Start || i.FetchCount != task.FetchCount) {
+		return false
+	}
+
+	recorded, err := url.Parse(i.URL)
+	return err == nil && recorded.RequestURI() == req.URL.RequestURI()
+}
+
+func (f failure) response(req *http.Request) (*http.Response, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &http.Response{
+		Status:     http.StatusText(f.statusCode),
+		StatusCode: f.statusCode,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Body:       http.NoBody,
+		Request:    req,
+	}, nil
+}
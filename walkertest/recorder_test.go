@@ -0,0 +1,229 @@
+package walkertest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"github.com/cyucelen/walker/walkertest"
+	"github.com/stretchr/testify/assert"
+)
+
+type bodySink struct {
+	mutex  sync.Mutex
+	bodies []string
+}
+
+func (b *bodySink) sink(res *http.Response, stop func()) error {
+	if res == nil {
+		return nil
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.bodies = append(b.bodies, fmt.Sprintf("%d %s", res.StatusCode, body))
+	return err
+}
+
+func (b *bodySink) sorted() []string {
+	sort.Strings(b.bodies)
+	return b.bodies
+}
+
+func walk(client *http.Client, url string, sink *bodySink) *walker.Walker[*http.Response] {
+	requestBuilder := func(start, fetchCount int) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, fmt.Sprintf("%s/books?page=%d&count=%d", url, start, fetchCount), http.NoBody)
+	}
+
+	w := walker.NewApiWalker(client, requestBuilder, sink.sink, walker.WithLimiter(walker.ConstantLimiter(30)))
+	w.Walk()
+	return w
+}
+
+func recordCassette(t *testing.T) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprintf(w, "page %s", r.URL.Query().Get("page"))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "books.json")
+	recorder, err := walkertest.NewRecorder(path, walkertest.ModeRecord)
+	assert.NoError(t, err)
+
+	sink := &bodySink{}
+	walk(recorder.Client(), server.URL, sink)
+
+	assert.Equal(t, []string{"200 page 0", "200 page 1", "200 page 2"}, sink.sorted())
+	assert.Len(t, recorder.Cassette().Interactions, 3)
+	assert.NoError(t, recorder.Save())
+
+	return path
+}
+
+func TestRecorderReplaysRecordedResponses(t *testing.T) {
+	path := recordCassette(t)
+
+	recorder, err := walkertest.NewRecorder(path, walkertest.ModeReplay)
+	assert.NoError(t, err)
+
+	sink := &bodySink{}
+	w := walk(recorder.Client(), "http://offline.invalid", sink)
+
+	assert.Empty(t, w.FailedTasks())
+	assert.Equal(t, []string{"200 page 0", "200 page 1", "200 page 2"}, sink.sorted())
+}
+
+func TestRecorderSimulatesRecordedLatency(t *testing.T) {
+	path := recordCassette(t)
+
+	recorder, err := walkertest.NewRecorder(path, walkertest.ModeReplay, walkertest.WithRecordedLatency())
+	assert.NoError(t, err)
+
+	began := time.Now()
+	walk(recorder.Client(), "http://offline.invalid", &bodySink{})
+
+	assert.GreaterOrEqual(t, time.Since(began), 10*time.Millisecond)
+}
+
+func TestRecorderInjectsFailures(t *testing.T) {
+	path := recordCassette(t)
+	errUpstream := errors.New("upstream is down")
+
+	recorder, err := walkertest.NewRecorder(
+		path,
+		walkertest.ModeReplay,
+		walkertest.WithError(1, 10, errUpstream),
+		walkertest.WithStatus(2, 10, http.StatusTooManyRequests),
+	)
+	assert.NoError(t, err)
+
+	sink := &bodySink{}
+	w := walk(recorder.Client(), "http://offline.invalid", sink)
+
+	assert.Len(t, w.FailedTasks(), 1)
+	assert.ErrorIs(t, w.FailedTasks()[0].Err, errUpstream)
+	assert.Equal(t, []string{"200 page 0", "429 "}, sink.sorted())
+}
+
+func TestRecorderReportsMissingInteractions(t *testing.T) {
+	path := recordCassette(t)
+
+	recorder, err := walkertest.NewRecorder(path, walkertest.ModeReplay)
+	assert.NoError(t, err)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://offline.invalid/unknown", http.NoBody)
+	_, err = recorder.Client().Do(req)
+
+	assert.ErrorIs(t, err, walkertest.ErrInteractionNotFound)
+}
+
+func walkDetails(client *http.Client, url string) []string {
+	get := func(ctx context.Context, path string) (string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+path, http.NoBody)
+		if err != nil {
+			return "", err
+		}
+		res, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		return string(body), err
+	}
+
+	bodies := []string{}
+	requestBuilder := func(ctx context.Context, start, fetchCount int) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, url+"/list", http.NoBody)
+	}
+	sink := func(ctx context.Context, res *http.Response, stop func()) error {
+		if res == nil {
+			return nil
+		}
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		bodies = append(bodies, string(body))
+
+		for id := 0; id < 2; id++ {
+			body, err := walker.Fetch(ctx, func(ctx context.Context) (string, error) {
+				return get(ctx, fmt.Sprintf("/detail/%d", id))
+			})
+			if err != nil {
+				return err
+			}
+			bodies = append(bodies, body)
+		}
+		return nil
+	}
+
+	walker.NewApiWalkerCtx(client, requestBuilder, sink, walker.WithLimiter(walker.ConstantLimiter(10))).Walk()
+	return bodies
+}
+
+func TestRecorderReplaysRequestsMadeInsideATask(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.URL.Path)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "details.json")
+	recorder, err := walkertest.NewRecorder(path, walkertest.ModeRecord)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/list", "/detail/0", "/detail/1"}, walkDetails(recorder.Client(), server.URL))
+	assert.NoError(t, recorder.Save())
+
+	recorder, err = walkertest.NewRecorder(path, walkertest.ModeReplay)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/list", "/detail/0", "/detail/1"}, walkDetails(recorder.Client(), "http://offline.invalid"))
+}
+
+func TestRecorderReplaysRepeatedRequestsInRecordedOrder(t *testing.T) {
+	var requests int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprint(w, "page")
+	}))
+	defer server.Close()
+
+	retryingWalk := func(client *http.Client, url string) (*bodySink, *walker.Walker[*http.Response]) {
+		requestBuilder := func(start, fetchCount int) (*http.Request, error) {
+			return http.NewRequest(http.MethodGet, url+"/books", http.NoBody)
+		}
+		sink := &bodySink{}
+		w := walker.NewApiWalker(client, requestBuilder, sink.sink, walker.WithLimiter(walker.ConstantLimiter(10)), walker.WithRetries(1, 0))
+		w.Walk()
+		return sink, w
+	}
+
+	path := filepath.Join(t.TempDir(), "retries.json")
+	recorder, err := walkertest.NewRecorder(path, walkertest.ModeRecord)
+	assert.NoError(t, err)
+	sink, _ := retryingWalk(recorder.Client(), server.URL)
+	assert.Equal(t, []string{"200 page"}, sink.bodies)
+	assert.Len(t, recorder.Cassette().Interactions, 2)
+	assert.NoError(t, recorder.Save())
+
+	recorder, err = walkertest.NewRecorder(path, walkertest.ModeReplay)
+	assert.NoError(t, err)
+	sink, w := retryingWalk(recorder.Client(), "http://offline.invalid")
+	assert.Empty(t, w.FailedTasks())
+	assert.Equal(t, []string{"200 page"}, sink.bodies)
+}
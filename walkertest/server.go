@@ -0,0 +1,244 @@
+package walkertest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cyucelen/walker"
+)
+
+type Style int
+
+const (
+	OffsetStyle Style = iota
+	CursorStyle
+	TokenStyle
+	LinkHeaderStyle
+)
+
+type Item struct {
+	ID int `json:"id"`
+}
+
+type Page struct {
+	Items     []Item `json:"items"`
+	NextToken string `json:"next_token,omitempty"`
+}
+
+type ServerOption func(*Server)
+
+type Server struct {
+	*httptest.Server
+	style        Style
+	total        int
+	latency      time.Duration
+	errorEvery   int
+	errorStatus  int
+	failingPages map[int]int
+	requests     int64
+	mutex        sync.Mutex
+	served       map[int]int
+}
+
+func WithStyle(style Style) ServerOption {
+	return func(s *Server) {
+		s.style = style
+	}
+}
+
+func WithTotal(total int) ServerOption {
+	return func(s *Server) {
+		s.total = total
+	}
+}
+
+func WithServerLatency(latency time.Duration) ServerOption {
+	return func(s *Server) {
+		s.latency = latency
+	}
+}
+
+func WithErrorEvery(n, statusCode int) ServerOption {
+	return func(s *Server) {
+		s.errorEvery = n
+		s.errorStatus = statusCode
+	}
+}
+
+func WithFailingPage(position, statusCode int) ServerOption {
+	return func(s *Server) {
+		s.failingPages[position] = statusCode
+	}
+}
+
+func NewServer(options ...ServerOption) *Server {
+	server := &Server{
+		style:        OffsetStyle,
+		total:        100,
+		failingPages: make(map[int]int),
+		served:       make(map[int]int),
+	}
+
+	for _, option := range options {
+		option(server)
+	}
+
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+	return server
+}
+
+func (s *Server) Requests() int {
+	return int(atomic.LoadInt64(&s.requests))
+}
+
+func (s *Server) ServedCount(position int) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.served[position]
+}
+
+func (s *Server) RequestBuilder() walker.RequestBuilder {
+	return func(start, fetchCount int) (*http.Request, error) {
+		var url string
+		switch s.style {
+		case CursorStyle:
+			url = fmt.Sprintf("%s/items?start=%d&count=%d", s.URL, start, fetchCount)
+		case TokenStyle:
+			url = fmt.Sprintf("%s/items?token=%s&count=%d", s.URL, encodeToken(start), fetchCount)
+		default:
+			url = fmt.Sprintf("%s/items?page=%d&per_page=%d", s.URL, start, fetchCount)
+		}
+		return http.NewRequest(http.MethodGet, url, http.NoBody)
+	}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	requestNumber := atomic.AddInt64(&s.requests, 1)
+
+	if s.latency > 0 {
+		select {
+		case <-time.After(s.latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	position, offset, count, err := s.parse(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mutex.Lock()
+	s.served[position]++
+	s.mutex.Unlock()
+
+	if statusCode, ok := s.failingPages[position]; ok {
+		s.fail(w, statusCode)
+		return
+	}
+
+	if s.errorEvery > 0 && requestNumber%int64(s.errorEvery) == 0 {
+		s.fail(w, s.errorStatus)
+		return
+	}
+
+	page := Page{Items: make([]Item, 0, count)}
+	for id := offset + 1; id <= min(offset+count, s.total); id++ {
+		page.Items = append(page.Items, Item{ID: id})
+	}
+
+	next := offset + count
+	if next < s.total {
+		switch s.style {
+		case TokenStyle:
+			page.NextToken = encodeToken(next)
+		case LinkHeaderStyle:
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=%d&per_page=%d>; rel="next"`, s.URL, position+1, count))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+func (s *Server) parse(r *http.Request) (position, offset, count int, err error) {
+	query := r.URL.Query()
+
+	switch s.style {
+	case CursorStyle:
+		if offset, err = queryInt(query.Get("start"), 0); err != nil {
+			return
+		}
+		count, err = queryInt(query.Get("count"), 10)
+		return offset, offset, count, err
+	case TokenStyle:
+		if offset, err = decodeToken(query.Get("token")); err != nil {
+			return
+		}
+		count, err = queryInt(query.Get("count"), 10)
+		return offset, offset, count, err
+	default:
+		if position, err = queryInt(query.Get("page"), 0); err != nil {
+			return
+		}
+		count, err = queryInt(query.Get("per_page"), 10)
+		return position, position * count, count, err
+	}
+}
+
+func (s *Server) fail(w http.ResponseWriter, statusCode int) {
+	if statusCode == http.StatusTooManyRequests {
+		w.Header().Set("Retry-After", "1")
+	}
+	http.Error(w, http.StatusText(statusCode), statusCode)
+}
+
+func queryInt(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func encodeToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodeToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(decoded))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func DecodePage(res *http.Response) (Page, error) {
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Page{}, fmt.Errorf("walkertest: unexpected status %d", res.StatusCode)
+	}
+
+	var page Page
+	err := json.NewDecoder(res.Body).Decode(&page)
+	return page, err
+}
@@ -0,0 +1,160 @@
+package walkertest_test
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyucelen/walker"
+	"github.com/cyucelen/walker/walkertest"
+	"github.com/stretchr/testify/assert"
+)
+
+type itemSink struct {
+	mutex sync.Mutex
+	ids   []int
+}
+
+func (i *itemSink) sink(res *http.Response, stop func()) error {
+	page, err := walkertest.DecodePage(res)
+	if err != nil {
+		return err
+	}
+
+	if len(page.Items) == 0 {
+		stop()
+		return nil
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	for _, item := range page.Items {
+		i.ids = append(i.ids, item.ID)
+	}
+	return nil
+}
+
+func (i *itemSink) sorted() []int {
+	sort.Ints(i.ids)
+	return i.ids
+}
+
+func expectedIDs(total int) []int {
+	ids := make([]int, total)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	return ids
+}
+
+func TestServerWithWalkerPaginations(t *testing.T) {
+	tests := []struct {
+		style      walkertest.Style
+		pagination walker.Pagination
+	}{
+		{walkertest.OffsetStyle, walker.OffsetPagination{}},
+		{walkertest.CursorStyle, walker.CursorPagination{}},
+		{walkertest.TokenStyle, walker.CursorPagination{}},
+	}
+
+	for _, test := range tests {
+		server := walkertest.NewServer(walkertest.WithStyle(test.style), walkertest.WithTotal(95))
+
+		sink := &itemSink{}
+		w := walker.NewApiWalker(
+			http.DefaultClient,
+			server.RequestBuilder(),
+			sink.sink,
+			walker.WithPagination(test.pagination),
+			walker.WithParallelism(3),
+		)
+		w.Walk()
+		server.Close()
+
+		assert.Empty(t, w.FailedTasks())
+		assert.Equal(t, expectedIDs(95), sink.sorted())
+	}
+}
+
+func TestServerTokenStyleFollowsNextToken(t *testing.T) {
+	server := walkertest.NewServer(walkertest.WithStyle(walkertest.TokenStyle), walkertest.WithTotal(25))
+	defer server.Close()
+
+	ids := make([]int, 0)
+	url := server.URL + "/items?count=10"
+	for {
+		res, err := http.Get(url)
+		assert.NoError(t, err)
+		page, err := walkertest.DecodePage(res)
+		assert.NoError(t, err)
+
+		for _, item := range page.Items {
+			ids = append(ids, item.ID)
+		}
+		if page.NextToken == "" {
+			break
+		}
+		url = server.URL + "/items?count=10&token=" + page.NextToken
+	}
+
+	assert.Equal(t, expectedIDs(25), ids)
+	assert.Equal(t, 3, server.Requests())
+}
+
+func TestServerLinkHeaderStyle(t *testing.T) {
+	server := walkertest.NewServer(walkertest.WithStyle(walkertest.LinkHeaderStyle), walkertest.WithTotal(25))
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/items?page=1&per_page=10")
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, `<`+server.URL+`/items?page=2&per_page=10>; rel="next"`, res.Header.Get("Link"))
+
+	res, err = http.Get(server.URL + "/items?page=2&per_page=10")
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Empty(t, res.Header.Get("Link"))
+}
+
+func TestServerInjectsErrors(t *testing.T) {
+	server := walkertest.NewServer(
+		walkertest.WithTotal(100),
+		walkertest.WithErrorEvery(4, http.StatusInternalServerError),
+		walkertest.WithFailingPage(1, http.StatusTooManyRequests),
+	)
+	defer server.Close()
+
+	sink := &itemSink{}
+	w := walker.NewApiWalker(
+		http.DefaultClient,
+		server.RequestBuilder(),
+		sink.sink,
+		walker.WithLimiter(walker.ConstantLimiter(100)),
+		walker.WithParallelism(1),
+	)
+	w.Walk()
+
+	failedStarts := make([]int, 0)
+	for _, failedTask := range w.FailedTasks() {
+		failedStarts = append(failedStarts, failedTask.Start)
+	}
+	sort.Ints(failedStarts)
+
+	assert.Equal(t, []int{1, 3, 7}, failedStarts)
+	assert.Equal(t, 1, server.ServedCount(1))
+	assert.Len(t, sink.ids, 70)
+}
+
+func TestServerLatency(t *testing.T) {
+	server := walkertest.NewServer(walkertest.WithServerLatency(20 * time.Millisecond))
+	defer server.Close()
+
+	began := time.Now()
+	res, err := http.Get(server.URL + "/items")
+	assert.NoError(t, err)
+	res.Body.Close()
+
+	assert.GreaterOrEqual(t, time.Since(began), 20*time.Millisecond)
+}